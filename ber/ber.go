@@ -0,0 +1,155 @@
+// Package ber implements the subset of the Basic Encoding Rules used by SNMP,
+// as restricted by RFC 3416 and RFC 3417, for the SMI types
+package ber
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Tag is the identifier octet of a BER encoding. SNMP only uses single octet
+// identifiers.
+type Tag byte
+
+// Universal and application tags of the SMI types, and the context-specific
+// tags of the exceptions in a response, as defined in RFC 3416
+const (
+	TagInteger          Tag = 0x02
+	TagOctetString      Tag = 0x04
+	TagNull             Tag = 0x05
+	TagObjectIdentifier Tag = 0x06
+	TagSequence         Tag = 0x30
+	TagIpAddress        Tag = 0x40
+	TagCounter32        Tag = 0x41
+	TagGauge32          Tag = 0x42 // Also Unsigned32
+	TagTimeTicks        Tag = 0x43
+	TagOpaque           Tag = 0x44
+	TagNsapAddress      Tag = 0x45 // Obsolete, from RFC 1442
+	TagCounter64        Tag = 0x46
+	TagUInteger32       Tag = 0x47 // Obsolete, from RFC 1442
+	TagNoSuchObject     Tag = 0x80
+	TagNoSuchInstance   Tag = 0x81
+	TagEndOfMibView     Tag = 0x82
+)
+
+const tagConstructed = 0x20
+
+var tagNames = map[Tag]string{
+	TagInteger:          "INTEGER",
+	TagOctetString:      "OCTET STRING",
+	TagNull:             "NULL",
+	TagObjectIdentifier: "OBJECT IDENTIFIER",
+	TagSequence:         "SEQUENCE",
+	TagIpAddress:        "IpAddress",
+	TagCounter32:        "Counter32",
+	TagGauge32:          "Gauge32",
+	TagTimeTicks:        "TimeTicks",
+	TagOpaque:           "Opaque",
+	TagNsapAddress:      "NsapAddress",
+	TagCounter64:        "Counter64",
+	TagUInteger32:       "UInteger32",
+	TagNoSuchObject:     "noSuchObject",
+	TagNoSuchInstance:   "noSuchInstance",
+	TagEndOfMibView:     "endOfMibView",
+}
+
+func (t Tag) String() string {
+	if name, ok := tagNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("Tag(0x%02x)", byte(t))
+}
+
+// Constructed returns whether the encoding contains other encodings
+func (t Tag) Constructed() bool {
+	return t&tagConstructed != 0
+}
+
+// ErrMalformed is wrapped by every error returned for data that is not valid
+// BER, so that truncated or corrupt input can be told apart from values that
+// cannot be converted
+var ErrMalformed = errors.New("Malformed BER")
+
+func malformedf(format string, a ...interface{}) error {
+	return fmt.Errorf("%w: "+format, append([]interface{}{ErrMalformed}, a...)...)
+}
+
+// maxLength limits the length of an encoding, which no SNMP message comes
+// close to, so that the length always fits in an int
+const maxLength = 1<<31 - 1
+
+// ReadTLV splits the first encoding from the data into its tag and contents,
+// and returns the data that follows it. The indefinite length form is not
+// allowed, as in SNMP.
+func ReadTLV(data []byte) (tag Tag, contents []byte, rest []byte, err error) {
+	if len(data) < 2 {
+		return 0, nil, nil, malformedf("Truncated header of %d octets", len(data))
+	}
+	tag = Tag(data[0])
+	if tag&0x1f == 0x1f {
+		return 0, nil, nil, malformedf("Multiple octet tag 0x%02x", data[0])
+	}
+	length, offset := int(data[1]), 2
+	if length&0x80 != 0 {
+		numOctets := length & 0x7f
+		if numOctets == 0 {
+			return 0, nil, nil, malformedf("Indefinite length for %s", tag)
+		}
+		if numOctets > 4 {
+			return 0, nil, nil, malformedf("Length of %d octets for %s", numOctets, tag)
+		}
+		if len(data) < offset+numOctets {
+			return 0, nil, nil, malformedf("Truncated length for %s", tag)
+		}
+		var long uint64
+		for _, b := range data[offset : offset+numOctets] {
+			long = long<<8 | uint64(b)
+		}
+		if long > maxLength {
+			return 0, nil, nil, malformedf("Length %d for %s is too long", long, tag)
+		}
+		length, offset = int(long), offset+numOctets
+	}
+	if len(data)-offset < length {
+		return 0, nil, nil, malformedf("%s has length %d, but only %d octets remain", tag, length, len(data)-offset)
+	}
+	return tag, data[offset : offset+length], data[offset+length:], nil
+}
+
+// ReadExpected is like ReadTLV, but fails if the tag is not the expected one
+func ReadExpected(data []byte, expected Tag) (contents []byte, rest []byte, err error) {
+	tag, contents, rest, err := ReadTLV(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tag != expected {
+		return nil, nil, malformedf("Expected %s, got %s", expected, tag)
+	}
+	return contents, rest, nil
+}
+
+// AppendLength appends the length in the definite form, using the short form
+// where possible
+func AppendLength(dst []byte, length int) []byte {
+	if length < 0x80 {
+		return append(dst, byte(length))
+	}
+	var octets [4]byte
+	n := 0
+	for l := length; l > 0; l >>= 8 {
+		n++
+	}
+	for i := n - 1; i >= 0; i-- {
+		octets[i] = byte(length)
+		length >>= 8
+	}
+	dst = append(dst, 0x80|byte(n))
+	return append(dst, octets[:n]...)
+}
+
+// AppendTLV appends the encoding with the given tag and contents
+func AppendTLV(dst []byte, tag Tag, contents []byte) []byte {
+	dst = append(dst, byte(tag))
+	dst = AppendLength(dst, len(contents))
+	return append(dst, contents...)
+}
@@ -0,0 +1,158 @@
+package ber
+
+import (
+	"fmt"
+
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// applicationTypes are the types that are encoded with an application tag.
+// Unsigned32 is not included, since every unsigned type is derived from the
+// well-known Unsigned32, and is encoded as a Gauge32 by its base type.
+var applicationTypes = []struct {
+	identity string
+	tag      Tag
+}{
+	{"IpAddress", TagIpAddress},
+	{"Counter32", TagCounter32},
+	{"RFC1155-SMI::Counter", TagCounter32},
+	{"Gauge32", TagGauge32},
+	{"RFC1155-SMI::Gauge", TagGauge32},
+	{"TimeTicks", TagTimeTicks},
+	{"Opaque", TagOpaque},
+	{"Counter64", TagCounter64},
+	{"NsapAddress", TagNsapAddress},
+	{"UInteger32", TagUInteger32},
+}
+
+// TypeTag returns the tag that values of the type are encoded with. Types
+// derived from an application type use its tag, and others the tag of their
+// base type.
+func TypeTag(t *models.Type) (Tag, error) {
+	if t == nil {
+		return 0, fmt.Errorf("%w: no type", models.ErrInvalidType)
+	}
+	for _, a := range applicationTypes {
+		if t.DerivesFrom(a.identity) {
+			return a.tag, nil
+		}
+	}
+	switch t.BaseType {
+	case types.BaseTypeInteger32, types.BaseTypeEnum:
+		return TagInteger, nil
+	case types.BaseTypeOctetString, types.BaseTypeBits:
+		return TagOctetString, nil
+	case types.BaseTypeObjectIdentifier:
+		return TagObjectIdentifier, nil
+	case types.BaseTypeUnsigned32:
+		return TagGauge32, nil
+	case types.BaseTypeUnsigned64:
+		return TagCounter64, nil
+	}
+	return 0, fmt.Errorf("%w: %s has no SNMP encoding", models.ErrInvalidType, t.BaseType)
+}
+
+var tagTypes = map[Tag]models.Type{
+	TagInteger:          {BaseType: types.BaseTypeInteger32, Name: "Integer32"},
+	TagOctetString:      {BaseType: types.BaseTypeOctetString, Name: "OctetString"},
+	TagObjectIdentifier: {BaseType: types.BaseTypeObjectIdentifier, Name: "ObjectIdentifier"},
+	TagIpAddress:        {BaseType: types.BaseTypeOctetString, Module: "SNMPv2-SMI", Name: "IpAddress"},
+	TagCounter32:        {BaseType: types.BaseTypeUnsigned32, Module: "SNMPv2-SMI", Name: "Counter32"},
+	TagGauge32:          {BaseType: types.BaseTypeUnsigned32, Module: "SNMPv2-SMI", Name: "Gauge32"},
+	TagTimeTicks:        {BaseType: types.BaseTypeUnsigned32, Module: "SNMPv2-SMI", Name: "TimeTicks"},
+	TagOpaque:           {BaseType: types.BaseTypeOctetString, Module: "SNMPv2-SMI", Name: "Opaque"},
+	TagNsapAddress:      {BaseType: types.BaseTypeOctetString, Name: "NsapAddress"},
+	TagCounter64:        {BaseType: types.BaseTypeUnsigned64, Module: "SNMPv2-SMI", Name: "Counter64"},
+	TagUInteger32:       {BaseType: types.BaseTypeUnsigned32, Name: "UInteger32"},
+}
+
+// TagType returns the type that values with the tag are formatted with when
+// the type of the object is not known, or nil for NULL and the exceptions
+func TagType(tag Tag) *models.Type {
+	t, ok := tagTypes[tag]
+	if !ok {
+		return nil
+	}
+	return &t
+}
+
+// addressTypes are the octet string types without a DISPLAY-HINT whose values
+// are formatted as addresses
+var addressTypes = []string{
+	"RFC1065-SMI::IpAddress",
+	"RFC1155-SMI::IpAddress",
+	"SNMPv2-SMI::IpAddress",
+	"INET-ADDRESS-MIB::InetAddress",
+}
+
+// isPlainOctetString reports whether the string form of values of the type is
+// the octets themselves, rather than their formatted value
+func isPlainOctetString(t *models.Type) bool {
+	if t.BaseType != types.BaseTypeOctetString || t.Format != "" {
+		return false
+	}
+	for _, identity := range addressTypes {
+		if t.DerivesFrom(identity) {
+			return false
+		}
+	}
+	return true
+}
+
+// EncodeValue encodes a value of an object of the given type, with the tag
+// returned by TypeTag. A string is parsed with ParseValue of the type, such as
+// an enumeration label, a DISPLAY-HINT or a dotted IpAddress, unless the type
+// is a plain OCTET STRING, in which case it is the octets themselves.
+func EncodeValue(t *models.Type, value interface{}) ([]byte, error) {
+	tag, err := TypeTag(t)
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := value.(string); ok {
+		if isPlainOctetString(t) {
+			value = []byte(s)
+		} else if value, err = t.ParseValue(s); err != nil {
+			return nil, err
+		}
+	}
+	return Encode(tag, value)
+}
+
+// DecodeValue decodes the first SMI value from the data and formats it with
+// the type of the object, or with the type of its tag if t is nil. A value
+// whose tag does not match the type is formatted with the type of its tag,
+// and Err is set to an ErrInvalidType. NULL and the exceptions are returned
+// with a nil or Exception raw value, formatted as their name. An error is
+// returned only if the data is not valid BER.
+func DecodeValue(data []byte, t *models.Type, flags ...models.Format) (v models.Value, rest []byte, err error) {
+	tag, raw, rest, err := Decode(data)
+	if err != nil {
+		return
+	}
+	formatType := TagType(tag)
+	if formatType == nil {
+		v = models.Value{Format: models.ResolveFormat(flags), Raw: raw}
+		if v.Format != models.FormatNone {
+			v.Formatted = tag.String()
+		}
+		return
+	}
+	var mismatch error
+	if t != nil {
+		expected, tagErr := TypeTag(t)
+		switch {
+		case tagErr != nil:
+			mismatch = tagErr
+		case expected != tag:
+			mismatch = fmt.Errorf("%w: %s has tag %s, expected %s", models.ErrInvalidType, t.Name, tag, expected)
+		default:
+			formatType = t
+		}
+	}
+	v = formatType.FormatValue(raw, flags...)
+	if mismatch != nil {
+		v.Err = mismatch
+	}
+	return
+}
@@ -0,0 +1,129 @@
+package ber_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi/ber"
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+var (
+	displayStringType = models.Type{BaseType: types.BaseTypeOctetString, Format: "255a", Module: "SNMPv2-TC", Name: "DisplayString"}
+	macAddressType    = models.Type{BaseType: types.BaseTypeOctetString, Format: "1x:", Module: "SNMPv2-TC", Name: "MacAddress"}
+	dateAndTimeType   = models.Type{BaseType: types.BaseTypeOctetString, Format: "2d-1d-1d,1d:1d:1d.1d,1a1d:1d", Module: "SNMPv2-TC", Name: "DateAndTime"}
+	inetAddressType   = models.Type{BaseType: types.BaseTypeOctetString, Module: "INET-ADDRESS-MIB", Name: "InetAddress"}
+	ipAddressType     = models.Type{BaseType: types.BaseTypeOctetString, Module: "SNMPv2-SMI", Name: "IpAddress"}
+	timeTicksType     = models.Type{BaseType: types.BaseTypeUnsigned32, Module: "SNMPv2-SMI", Name: "TimeTicks"}
+	counter64Type     = models.Type{BaseType: types.BaseTypeUnsigned64, Module: "SNMPv2-SMI", Name: "Counter64"}
+	octetStringType   = models.Type{BaseType: types.BaseTypeOctetString}
+	statusType        = models.Type{BaseType: types.BaseTypeEnum, Enum: &models.Enum{BaseType: types.BaseTypeEnum, Values: []models.NamedNumber{{Name: "up", Value: 1}, {Name: "down", Value: 2}}}}
+	flagsType         = models.Type{BaseType: types.BaseTypeBits, Enum: &models.Enum{BaseType: types.BaseTypeBits, Values: []models.NamedNumber{{Name: "alpha", Value: 0}, {Name: "beta", Value: 1}, {Name: "delta", Value: 9}}}}
+)
+
+func TestTypeTag(t *testing.T) {
+	tests := []struct {
+		source string
+		typ    models.Type
+		tag    ber.Tag
+	}{
+		{"Enumeration", statusType, ber.TagInteger},
+		{"Textual convention", displayStringType, ber.TagOctetString},
+		{"BITS", flagsType, ber.TagOctetString},
+		{"IpAddress", ipAddressType, ber.TagIpAddress},
+		{"TimeTicks", timeTicksType, ber.TagTimeTicks},
+		{"Derived from TimeTicks", models.Type{BaseType: types.BaseTypeUnsigned32, Module: "SNMPv2-TC", Name: "TimeStamp", DerivedFrom: []string{"SNMPv2-SMI::TimeTicks"}}, ber.TagTimeTicks},
+		{"Unsigned32", models.Type{BaseType: types.BaseTypeUnsigned32}, ber.TagGauge32},
+		{"Counter64", counter64Type, ber.TagCounter64},
+	}
+	for _, test := range tests {
+		tag, err := ber.TypeTag(&test.typ)
+		if err != nil || tag != test.tag {
+			t.Errorf("%s: TypeTag = %s, %v, want %s", test.source, tag, err, test.tag)
+		}
+	}
+	if _, err := ber.TypeTag(nil); !errors.Is(err, models.ErrInvalidType) {
+		t.Errorf("TypeTag(nil) error = %v, want ErrInvalidType", err)
+	}
+	if tagType := ber.TagType(ber.TagTimeTicks); tagType == nil || tagType.Identity() != "SNMPv2-SMI::TimeTicks" {
+		t.Errorf("TagType(TimeTicks) = %+v, want SNMPv2-SMI::TimeTicks", tagType)
+	}
+	if tagType := ber.TagType(ber.TagNull); tagType != nil {
+		t.Errorf("TagType(NULL) = %+v, want nil", tagType)
+	}
+}
+
+func TestEncodeValue(t *testing.T) {
+	tests := []struct {
+		source    string
+		typ       models.Type
+		value     interface{}
+		encoded   []byte
+		formatted string
+	}{
+		{"Plain octet string", octetStringType, "public", []byte{0x04, 0x06, 'p', 'u', 'b', 'l', 'i', 'c'}, "70 75 62 6C 69 63"},
+		{"DisplayString", displayStringType, "eth0", []byte{0x04, 0x04, 'e', 't', 'h', '0'}, "eth0"},
+		{"MacAddress", macAddressType, "01:02:03:04:05:06", []byte{0x04, 0x06, 1, 2, 3, 4, 5, 6}, "01:02:03:04:05:06"},
+		{"DateAndTime", dateAndTimeType, "2024-5-26,13:30:15.0,-4:0", []byte{0x04, 0x0b, 0x07, 0xe8, 5, 26, 13, 30, 15, 0, '-', 4, 0}, "2024-5-26,13:30:15.0,-4:0"},
+		{"BITS", flagsType, "alpha delta", []byte{0x04, 0x02, 0x80, 0x40}, "alpha(0) delta(9)"},
+		{"InetAddress", inetAddressType, "192.0.2.1", []byte{0x04, 0x04, 192, 0, 2, 1}, "192.0.2.1"},
+		{"IpAddress", ipAddressType, "192.0.2.1", []byte{0x40, 0x04, 192, 0, 2, 1}, "192.0.2.1"},
+		{"Enumeration label", statusType, "down", []byte{0x02, 0x01, 0x02}, "down(2)"},
+		{"Enumeration value", statusType, 1, []byte{0x02, 0x01, 0x01}, "up(1)"},
+		{"Counter64", counter64Type, uint64(1 << 40), []byte{0x46, 0x06, 0x01, 0, 0, 0, 0, 0}, "1099511627776"},
+	}
+	for _, test := range tests {
+		encoded, err := ber.EncodeValue(&test.typ, test.value)
+		if err != nil {
+			t.Errorf("%s: EncodeValue(%v): %v", test.source, test.value, err)
+			continue
+		}
+		if !bytes.Equal(encoded, test.encoded) {
+			t.Errorf("%s: EncodeValue(%v) = % x, want % x", test.source, test.value, encoded, test.encoded)
+		}
+		v, rest, err := ber.DecodeValue(encoded, &test.typ, models.FormatEnumName|models.FormatEnumValue|models.FormatString)
+		if err != nil || v.Err != nil || len(rest) != 0 {
+			t.Errorf("%s: DecodeValue(% x) = %v, % x, %v", test.source, encoded, v.Err, rest, err)
+			continue
+		}
+		if v.Formatted != test.formatted {
+			t.Errorf("%s: DecodeValue(% x) formatted %q, want %q", test.source, encoded, v.Formatted, test.formatted)
+		}
+	}
+
+	for _, test := range []struct {
+		typ   models.Type
+		value string
+	}{
+		{flagsType, "alpha gamma"},
+		{macAddressType, "01:02:zz"},
+		{dateAndTimeType, "yesterday"},
+		{statusType, "testing"},
+	} {
+		if encoded, err := ber.EncodeValue(&test.typ, test.value); err == nil {
+			t.Errorf("EncodeValue(%s, %q) = % x, want error", test.typ.Name, test.value, encoded)
+		}
+	}
+}
+
+func TestDecodeValueMismatch(t *testing.T) {
+	// An INTEGER for an IpAddress is formatted as an INTEGER
+	v, _, err := ber.DecodeValue([]byte{0x02, 0x01, 0x05}, &ipAddressType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(v.Err, models.ErrInvalidType) || v.Raw != int64(5) || v.Formatted != "5" {
+		t.Errorf("DecodeValue INTEGER as IpAddress = %#v, %q with error %v, want 5 with ErrInvalidType", v.Raw, v.Formatted, v.Err)
+	}
+	// Without a type, the value is formatted with the type of its tag
+	v, _, err = ber.DecodeValue([]byte{0x43, 0x02, 0x01, 0xf4}, nil)
+	if err != nil || v.Err != nil || v.Formatted != "5s" {
+		t.Errorf("DecodeValue TimeTicks = %q with error %v, %v, want 5s", v.Formatted, v.Err, err)
+	}
+	v, _, err = ber.DecodeValue([]byte{0x81, 0x00}, &statusType)
+	if err != nil || v.Raw != ber.NoSuchInstance || v.Formatted != "noSuchInstance" {
+		t.Errorf("DecodeValue noSuchInstance = %#v, %q, %v", v.Raw, v.Formatted, err)
+	}
+}
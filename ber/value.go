@@ -0,0 +1,366 @@
+package ber
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"reflect"
+	"time"
+
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// Exception is the raw value of a varbind in a response that holds one of
+// the exceptions instead of a value
+type Exception Tag
+
+const (
+	NoSuchObject   = Exception(TagNoSuchObject)
+	NoSuchInstance = Exception(TagNoSuchInstance)
+	EndOfMibView   = Exception(TagEndOfMibView)
+)
+
+func (e Exception) String() string {
+	return Tag(e).String()
+}
+
+// Decode decodes the first SMI value from the data and returns its tag, its
+// raw value and the data that follows it. The raw value has the type that the
+// formatters in models expect: int64 for INTEGER and the 32-bit application
+// types, uint64 for Counter64, []byte for OCTET STRING, IpAddress, Opaque and
+// NsapAddress, types.Oid for OBJECT IDENTIFIER, nil for NULL and Exception for
+// the exceptions.
+func Decode(data []byte) (tag Tag, raw interface{}, rest []byte, err error) {
+	tag, contents, rest, err := ReadTLV(data)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	raw, err = decodeContents(tag, contents)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return tag, raw, rest, nil
+}
+
+func decodeContents(tag Tag, contents []byte) (interface{}, error) {
+	switch tag {
+	case TagInteger:
+		return decodeInteger(contents)
+	case TagOctetString, TagOpaque, TagNsapAddress:
+		return append([]byte{}, contents...), nil
+	case TagIpAddress:
+		if len(contents) != 4 {
+			return nil, malformedf("IpAddress has %d octets", len(contents))
+		}
+		return append([]byte{}, contents...), nil
+	case TagCounter32, TagGauge32, TagTimeTicks, TagUInteger32:
+		u, err := decodeUnsigned(tag, contents, 32)
+		return int64(u), err
+	case TagCounter64:
+		return decodeUnsigned(tag, contents, 64)
+	case TagObjectIdentifier:
+		return decodeOid(contents)
+	case TagNull, TagNoSuchObject, TagNoSuchInstance, TagEndOfMibView:
+		if len(contents) != 0 {
+			return nil, malformedf("%s has %d octets", tag, len(contents))
+		}
+		if tag == TagNull {
+			return nil, nil
+		}
+		return Exception(tag), nil
+	}
+	return nil, malformedf("Unknown value %s", tag)
+}
+
+func decodeInteger(contents []byte) (int64, error) {
+	if len(contents) == 0 {
+		return 0, malformedf("INTEGER has no octets")
+	}
+	if len(contents) > 8 {
+		return 0, malformedf("INTEGER of %d octets overflows int64", len(contents))
+	}
+	var i int64
+	if contents[0]&0x80 != 0 {
+		i = -1
+	}
+	for _, b := range contents {
+		i = i<<8 | int64(b)
+	}
+	return i, nil
+}
+
+// decodeUnsigned decodes an unsigned application type. Agents that encode a
+// value with the high bit set without the leading zero octet are tolerated,
+// since the value cannot be negative anyway.
+func decodeUnsigned(tag Tag, contents []byte, bits int) (uint64, error) {
+	if len(contents) == 0 {
+		return 0, malformedf("%s has no octets", tag)
+	}
+	for len(contents) > 1 && contents[0] == 0 {
+		contents = contents[1:]
+	}
+	if len(contents) > bits/8 {
+		return 0, malformedf("%s of %d octets overflows %d bits", tag, len(contents), bits)
+	}
+	var u uint64
+	for _, b := range contents {
+		u = u<<8 | uint64(b)
+	}
+	return u, nil
+}
+
+func decodeOid(contents []byte) (types.Oid, error) {
+	if len(contents) == 0 {
+		return nil, malformedf("OBJECT IDENTIFIER has no octets")
+	}
+	oid := make(types.Oid, 0, len(contents)+1)
+	var subId uint64
+	start := true
+	for i, b := range contents {
+		if start && b == 0x80 {
+			return nil, malformedf("Sub-identifier at offset %d is not minimally encoded", i)
+		}
+		subId = subId<<7 | uint64(b&0x7f)
+		if subId > math.MaxUint32+80 {
+			return nil, malformedf("Sub-identifier at offset %d overflows 32 bits", i)
+		}
+		if start = b&0x80 == 0; !start {
+			continue
+		}
+		if len(oid) == 0 {
+			switch {
+			case subId < 40:
+				oid = append(oid, 0, types.SmiSubId(subId))
+			case subId < 80:
+				oid = append(oid, 1, types.SmiSubId(subId-40))
+			default:
+				oid = append(oid, 2, types.SmiSubId(subId-80))
+			}
+		} else if subId > math.MaxUint32 {
+			return nil, malformedf("Sub-identifier at offset %d overflows 32 bits", i)
+		} else {
+			oid = append(oid, types.SmiSubId(subId))
+		}
+		subId = 0
+	}
+	if !start {
+		return nil, malformedf("OBJECT IDENTIFIER ends in the middle of a sub-identifier")
+	}
+	return oid, nil
+}
+
+// Encode encodes the raw value with the given tag. See Append.
+func Encode(tag Tag, raw interface{}) ([]byte, error) {
+	return Append(nil, tag, raw)
+}
+
+// Append appends the encoding of the raw value with the given tag. Besides the
+// raw types returned by Decode, it accepts a models.Value, the typed raw
+// values of the formatters in models, such as time.Duration for TimeTicks and
+// time.Time for a DateAndTime, and any Go integer type. The value of NULL and
+// the exceptions is ignored.
+func Append(dst []byte, tag Tag, raw interface{}) ([]byte, error) {
+	if v, ok := raw.(models.Value); ok {
+		raw = v.Raw
+	}
+	var contents []byte
+	switch tag {
+	case TagInteger:
+		i, err := toInt64(raw)
+		if err != nil {
+			return nil, err
+		}
+		contents = appendInteger(nil, i)
+	case TagCounter32, TagGauge32, TagTimeTicks, TagUInteger32, TagCounter64:
+		u, err := toUint64(raw)
+		if err != nil {
+			return nil, err
+		}
+		if tag != TagCounter64 && u > math.MaxUint32 {
+			return nil, fmt.Errorf("%w: %d overflows %s", models.ErrOutOfRange, u, tag)
+		}
+		contents = appendUnsigned(nil, u)
+	case TagOctetString, TagNsapAddress:
+		octets, err := toOctets(raw)
+		if err != nil {
+			return nil, err
+		}
+		contents = octets
+	case TagOpaque:
+		octets, err := toOctets(raw)
+		if err != nil {
+			if octets, err = models.EncodeOpaque(raw); err != nil {
+				return nil, err
+			}
+		}
+		contents = octets
+	case TagIpAddress:
+		octets, err := toIpAddress(raw)
+		if err != nil {
+			return nil, err
+		}
+		contents = octets
+	case TagObjectIdentifier:
+		oid, err := models.ToOid(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", models.ErrInvalidType, err)
+		}
+		if contents, err = appendOid(nil, oid); err != nil {
+			return nil, err
+		}
+	case TagNull, TagNoSuchObject, TagNoSuchInstance, TagEndOfMibView:
+	default:
+		return nil, fmt.Errorf("Cannot encode a value as %s", tag)
+	}
+	return AppendTLV(dst, tag, contents), nil
+}
+
+func appendInteger(dst []byte, i int64) []byte {
+	n := 1
+	for v := i; v > 127 || v < -128; v >>= 8 {
+		n++
+	}
+	for j := n - 1; j >= 0; j-- {
+		dst = append(dst, byte(i>>(8*uint(j))))
+	}
+	return dst
+}
+
+func appendUnsigned(dst []byte, u uint64) []byte {
+	n := 1
+	for v := u; v > 127; v >>= 8 {
+		n++
+	}
+	for j := n - 1; j >= 0; j-- {
+		if j >= 8 {
+			dst = append(dst, 0)
+		} else {
+			dst = append(dst, byte(u>>(8*uint(j))))
+		}
+	}
+	return dst
+}
+
+func appendSubId(dst []byte, subId uint64) []byte {
+	n := 1
+	for v := subId; v > 0x7f; v >>= 7 {
+		n++
+	}
+	for j := n - 1; j > 0; j-- {
+		dst = append(dst, 0x80|byte(subId>>(7*uint(j))))
+	}
+	return append(dst, byte(subId&0x7f))
+}
+
+// appendOid appends the contents of an OBJECT IDENTIFIER. An empty OID is
+// encoded as 0.0, as is usual for a null OID.
+func appendOid(dst []byte, oid types.Oid) ([]byte, error) {
+	switch len(oid) {
+	case 0:
+		return append(dst, 0), nil
+	case 1:
+		oid = types.Oid{oid[0], 0}
+	}
+	if oid[0] > 2 || (oid[0] < 2 && oid[1] >= 40) {
+		return nil, fmt.Errorf("%w: OID %s cannot be encoded", models.ErrOutOfRange, oid)
+	}
+	dst = appendSubId(dst, 40*uint64(oid[0])+uint64(oid[1]))
+	for _, subId := range oid[2:] {
+		dst = appendSubId(dst, uint64(subId))
+	}
+	return dst, nil
+}
+
+func toInt64(raw interface{}) (int64, error) {
+	switch r := raw.(type) {
+	case time.Duration:
+		return int64(r / (10 * time.Millisecond)), nil
+	case bool:
+		if r {
+			return int64(models.TruthValueTrue), nil
+		}
+		return int64(models.TruthValueFalse), nil
+	}
+	rv := reflect.ValueOf(raw)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("%w: %d overflows INTEGER", models.ErrOutOfRange, rv.Uint())
+		}
+		return int64(rv.Uint()), nil
+	}
+	return 0, fmt.Errorf("%w: %T is not an integer", models.ErrInvalidType, raw)
+}
+
+func toUint64(raw interface{}) (uint64, error) {
+	switch r := raw.(type) {
+	case time.Duration:
+		raw = int64(r / (10 * time.Millisecond))
+	case models.TimeStamp:
+		return uint64(r), nil
+	}
+	rv := reflect.ValueOf(raw)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.Int() < 0 {
+			return 0, fmt.Errorf("%w: %d is negative", models.ErrOutOfRange, rv.Int())
+		}
+		return uint64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint(), nil
+	}
+	return 0, fmt.Errorf("%w: %T is not an integer", models.ErrInvalidType, raw)
+}
+
+func toOctets(raw interface{}) ([]byte, error) {
+	switch r := raw.(type) {
+	case []byte:
+		return r, nil
+	case string:
+		return []byte(r), nil
+	case net.IP:
+		if ip4 := r.To4(); ip4 != nil {
+			return ip4, nil
+		}
+		return r, nil
+	case net.HardwareAddr:
+		return r, nil
+	case models.InetAddress:
+		return r.Octets(), nil
+	case time.Time:
+		return models.EncodeDateAndTime(r)
+	case []int:
+		octets := make([]byte, len(r))
+		for i, b := range r {
+			if b < 0 || b > 0xff {
+				return nil, fmt.Errorf("%w: %d is not an octet", models.ErrOutOfRange, b)
+			}
+			octets[i] = byte(b)
+		}
+		return octets, nil
+	}
+	return nil, fmt.Errorf("%w: %T is not an OCTET STRING", models.ErrInvalidType, raw)
+}
+
+func toIpAddress(raw interface{}) ([]byte, error) {
+	switch r := raw.(type) {
+	case string:
+		raw = net.ParseIP(r)
+	case models.InetAddress:
+		raw = r.Octets()
+	}
+	if ip, ok := raw.(net.IP); ok && ip.To4() != nil {
+		return ip.To4(), nil
+	}
+	octets, err := toOctets(raw)
+	if err != nil {
+		return nil, err
+	}
+	if len(octets) != 4 {
+		return nil, fmt.Errorf("%w: IpAddress must have 4 octets, not %d", models.ErrInvalidSize, len(octets))
+	}
+	return octets, nil
+}
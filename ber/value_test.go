@@ -0,0 +1,81 @@
+package ber_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi/ber"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+var valueTests = []struct {
+	source  string
+	tag     ber.Tag
+	raw     interface{}
+	encoded []byte
+}{
+	{"Zero", ber.TagInteger, int64(0), []byte{0x02, 0x01, 0x00}},
+	{"Positive with high bit", ber.TagInteger, int64(128), []byte{0x02, 0x02, 0x00, 0x80}},
+	{"Negative", ber.TagInteger, int64(-129), []byte{0x02, 0x02, 0xff, 0x7f}},
+	{"Maximum Counter32", ber.TagCounter32, int64(4294967295), []byte{0x41, 0x05, 0x00, 0xff, 0xff, 0xff, 0xff}},
+	{"Gauge32", ber.TagGauge32, int64(100000000), []byte{0x42, 0x04, 0x05, 0xf5, 0xe1, 0x00}},
+	{"TimeTicks", ber.TagTimeTicks, int64(500), []byte{0x43, 0x02, 0x01, 0xf4}},
+	{"Maximum Counter64", ber.TagCounter64, uint64(18446744073709551615), []byte{0x46, 0x09, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+	{"OCTET STRING", ber.TagOctetString, []byte("public"), []byte{0x04, 0x06, 'p', 'u', 'b', 'l', 'i', 'c'}},
+	{"Empty OCTET STRING", ber.TagOctetString, []byte{}, []byte{0x04, 0x00}},
+	{"IpAddress", ber.TagIpAddress, []byte{192, 0, 2, 1}, []byte{0x40, 0x04, 0xc0, 0x00, 0x02, 0x01}},
+	{"Opaque", ber.TagOpaque, []byte{0x9f, 0x78, 0x04, 0x3f, 0xc0, 0x00, 0x00}, []byte{0x44, 0x07, 0x9f, 0x78, 0x04, 0x3f, 0xc0, 0x00, 0x00}},
+	{"OBJECT IDENTIFIER", ber.TagObjectIdentifier, types.Oid{1, 3, 6, 1, 2, 1, 1, 3, 0}, []byte{0x06, 0x08, 0x2b, 0x06, 0x01, 0x02, 0x01, 0x01, 0x03, 0x00}},
+	{"Multi-octet sub-identifiers", ber.TagObjectIdentifier, types.Oid{2, 999, 4294967295}, []byte{0x06, 0x07, 0x88, 0x37, 0x8f, 0xff, 0xff, 0xff, 0x7f}},
+	{"NULL", ber.TagNull, nil, []byte{0x05, 0x00}},
+	{"noSuchObject", ber.TagNoSuchObject, ber.NoSuchObject, []byte{0x80, 0x00}},
+	{"endOfMibView", ber.TagEndOfMibView, ber.EndOfMibView, []byte{0x82, 0x00}},
+}
+
+func TestValue(t *testing.T) {
+	for _, test := range valueTests {
+		encoded, err := ber.Encode(test.tag, test.raw)
+		if err != nil {
+			t.Errorf("%s: Encode: %v", test.source, err)
+		} else if !bytes.Equal(encoded, test.encoded) {
+			t.Errorf("%s: Encode = % x, want % x", test.source, encoded, test.encoded)
+		}
+		tag, raw, rest, err := ber.Decode(append(test.encoded, 0xff))
+		if err != nil {
+			t.Errorf("%s: Decode: %v", test.source, err)
+			continue
+		}
+		if tag != test.tag || !reflect.DeepEqual(raw, test.raw) || !bytes.Equal(rest, []byte{0xff}) {
+			t.Errorf("%s: Decode = %s %#v % x, want %s %#v ff", test.source, tag, raw, rest, test.tag, test.raw)
+		}
+	}
+}
+
+var malformedTests = []struct {
+	source  string
+	encoded []byte
+}{
+	{"Empty", nil},
+	{"Truncated contents", []byte{0x04, 0x05, 'a'}},
+	{"Indefinite length", []byte{0x04, 0x80, 0x00, 0x00}},
+	{"Truncated long length", []byte{0x04, 0x82, 0x01}},
+	{"Empty INTEGER", []byte{0x02, 0x00}},
+	{"INTEGER overflows int64", []byte{0x02, 0x09, 0x01, 0, 0, 0, 0, 0, 0, 0, 0}},
+	{"Counter32 overflows 32 bits", []byte{0x41, 0x05, 0x01, 0, 0, 0, 0}},
+	{"Short IpAddress", []byte{0x40, 0x03, 192, 0, 2}},
+	{"Unterminated sub-identifier", []byte{0x06, 0x02, 0x2b, 0x86}},
+	{"Sub-identifier overflows 32 bits", []byte{0x06, 0x06, 0x2b, 0x90, 0x80, 0x80, 0x80, 0x00}},
+	{"NULL with contents", []byte{0x05, 0x01, 0x00}},
+	{"Unknown tag", []byte{0x48, 0x00}},
+}
+
+func TestMalformed(t *testing.T) {
+	for _, test := range malformedTests {
+		_, _, _, err := ber.Decode(test.encoded)
+		if !errors.Is(err, ber.ErrMalformed) {
+			t.Errorf("%s: Decode(% x) error = %v, want ErrMalformed", test.source, test.encoded, err)
+		}
+	}
+}
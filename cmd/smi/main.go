@@ -45,7 +45,10 @@ func main() {
 }
 
 func Init() {
-	gosmi.Init()
+	if err := gosmi.Init(); err != nil {
+		fmt.Printf("Init Error: %s\n", err)
+		return
+	}
 
 	for _, path := range paths {
 		gosmi.AppendPath(path)
@@ -1,15 +1,18 @@
 package gosmi
 
 import (
+	"errors"
 	"os"
 
 	"github.com/sleepinggenius2/gosmi/smi"
 )
 
-func Init() {
+// Init initializes the library, returning an error if that fails
+func Init() error {
 	if !smi.Init("gosmi") {
-		panic("Failed to initialize")
+		return errors.New("Failed to initialize")
 	}
+	return nil
 }
 
 func Exit() { smi.Exit() }
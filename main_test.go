@@ -0,0 +1,47 @@
+package gosmi_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi"
+)
+
+// testModules are loaded from testdata/mibs, which holds abridged copies of
+// the standard modules and the TEST-* fixtures
+var testModules = []string{
+	"SNMPv2-MIB",
+	"IF-MIB",
+	"IP-MIB",
+	"TEST-MIB",
+	"TEST-V1-MIB",
+	"TEST-ALIAS-MIB",
+	"TEST-ALIAS-USER-MIB",
+	"TEST-COMPLIANCE-MIB",
+	"TEST-SEARCH-MIB",
+}
+
+// initTestModules initializes the library and loads the test modules
+func initTestModules() error {
+	if err := gosmi.Init(); err != nil {
+		return err
+	}
+	gosmi.SetPath("testdata/mibs")
+	for _, module := range testModules {
+		if _, err := gosmi.LoadModule(module); err != nil {
+			return fmt.Errorf("Load %s: %w", module, err)
+		}
+	}
+	return nil
+}
+
+func TestMain(m *testing.M) {
+	if err := initTestModules(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	code := m.Run()
+	gosmi.Exit()
+	os.Exit(code)
+}
@@ -0,0 +1,522 @@
+package gosmi
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+var ErrNotWritable = errors.New("Object is not writable")
+
+// fieldKind is the Go representation of a struct field bound to an object
+type fieldKind int
+
+const (
+	fieldString fieldKind = iota
+	fieldInt
+	fieldUint
+	fieldBool
+	fieldBytes
+	fieldIP
+	fieldDuration
+	fieldTime
+	fieldOid
+	fieldValue
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+	ipType       = reflect.TypeOf(net.IP(nil))
+	oidType      = reflect.TypeOf(types.Oid(nil))
+	valueType    = reflect.TypeOf(models.Value{})
+)
+
+type fieldBinding struct {
+	index     int
+	name      string
+	node      SmiNode
+	kind      fieldKind
+	omitEmpty bool
+}
+
+type structBinding struct {
+	fields []fieldBinding
+	byOid  map[string][]int // Positions of the fields bound to each object
+}
+
+// structBindings caches the bindings of struct types by reflect.Type. Only
+// successful bindings are cached, so that a struct can be bound once the
+// modules it refers to are loaded.
+var structBindings sync.Map
+
+func getFieldKind(t reflect.Type) (fieldKind, bool) {
+	switch t {
+	case durationType:
+		return fieldDuration, true
+	case timeType:
+		return fieldTime, true
+	case ipType:
+		return fieldIP, true
+	case oidType:
+		return fieldOid, true
+	case valueType:
+		return fieldValue, true
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return fieldString, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fieldInt, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fieldUint, true
+	case reflect.Bool:
+		return fieldBool, true
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return fieldBytes, true
+		}
+	}
+	return 0, false
+}
+
+// durationTypes are the types whose values are held in time.Duration fields
+var durationTypes = []string{
+	"RFC1065-SMI::TimeTicks",
+	"RFC1155-SMI::TimeTicks",
+	"SNMPv2-SMI::TimeTicks",
+	"SNMPv2-TC::TimeInterval",
+	"SNMPv2-TC::TimeStamp",
+}
+
+// ipTypes are the types whose values are held in net.IP fields
+var ipTypes = []string{
+	"RFC1065-SMI::IpAddress",
+	"RFC1155-SMI::IpAddress",
+	"SNMPv2-SMI::IpAddress",
+	"INET-ADDRESS-MIB::InetAddress",
+	"INET-ADDRESS-MIB::InetAddressIPv4",
+	"INET-ADDRESS-MIB::InetAddressIPv6",
+}
+
+func derivesFromAny(t *models.Type, identities []string) bool {
+	for _, identity := range identities {
+		if t.DerivesFrom(identity) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDurationType(t *models.Type) bool {
+	return derivesFromAny(t, durationTypes)
+}
+
+func isIPType(t *models.Type) bool {
+	return derivesFromAny(t, ipTypes)
+}
+
+// isPlainOctetString reports whether the string form of values of the type is
+// the octets themselves, rather than their formatted value
+func isPlainOctetString(t *models.Type) bool {
+	return t.BaseType == types.BaseTypeOctetString && t.Format == "" && !isIPType(t)
+}
+
+// canBind reports whether a field of the kind can hold values of the type
+func canBind(kind fieldKind, t *models.Type) bool {
+	switch kind {
+	case fieldValue:
+		return true
+	case fieldString:
+		switch t.BaseType {
+		case types.BaseTypeOctetString, types.BaseTypeEnum, types.BaseTypeBits, types.BaseTypeObjectIdentifier:
+			return true
+		}
+	case fieldInt:
+		switch t.BaseType {
+		case types.BaseTypeInteger32, types.BaseTypeInteger64, types.BaseTypeUnsigned32, types.BaseTypeEnum:
+			return true
+		}
+	case fieldUint:
+		return t.BaseType == types.BaseTypeUnsigned32 || t.BaseType == types.BaseTypeUnsigned64
+	case fieldBool:
+		return t.DerivesFrom("SNMPv2-TC::TruthValue")
+	case fieldBytes:
+		return t.BaseType == types.BaseTypeOctetString || t.BaseType == types.BaseTypeBits
+	case fieldIP:
+		return t.BaseType == types.BaseTypeOctetString && isIPType(t)
+	case fieldDuration:
+		return isDurationType(t)
+	case fieldTime:
+		return t.DerivesFrom("SNMPv2-TC::DateAndTime")
+	case fieldOid:
+		return t.BaseType == types.BaseTypeObjectIdentifier
+	}
+	return false
+}
+
+func bindStruct(t reflect.Type) (*structBinding, error) {
+	if b, ok := structBindings.Load(t); ok {
+		return b.(*structBinding), nil
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Cannot bind %s: Not a struct", t)
+	}
+	b := &structBinding{byOid: make(map[string][]int)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("snmp")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		if field.PkgPath != "" {
+			return nil, fmt.Errorf("Cannot bind %s.%s: Field is not exported", t, field.Name)
+		}
+		options := strings.Split(tag, ",")
+		node, err := GetNode(options[0])
+		if err != nil {
+			return nil, fmt.Errorf("Cannot bind %s.%s: %w", t, field.Name, err)
+		}
+		if node.Type == nil || (node.Kind != types.NodeScalar && node.Kind != types.NodeColumn) {
+			return nil, fmt.Errorf("Cannot bind %s.%s: %s is not a scalar or column", t, field.Name, node.Name)
+		}
+		kind, ok := getFieldKind(field.Type)
+		if !ok || !canBind(kind, node.Type) {
+			return nil, fmt.Errorf("Cannot bind %s.%s: %s cannot hold %s values of type %s", t, field.Name, field.Type, node.Name, node.Type.BaseType)
+		}
+		binding := fieldBinding{index: i, name: field.Name, node: node, kind: kind}
+		for _, option := range options[1:] {
+			if option == "omitempty" {
+				binding.omitEmpty = true
+			}
+		}
+		key := oidKey(node.Oid)
+		b.byOid[key] = append(b.byOid[key], len(b.fields))
+		b.fields = append(b.fields, binding)
+	}
+	structBindings.Store(t, b)
+	return b, nil
+}
+
+func structValue(v interface{}) (reflect.Value, *structBinding, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return reflect.Value{}, nil, fmt.Errorf("Cannot unmarshal into %T: Not a non-nil pointer", v)
+	}
+	rv = rv.Elem()
+	b, err := bindStruct(rv.Type())
+	if err != nil {
+		return reflect.Value{}, nil, err
+	}
+	return rv, b, nil
+}
+
+// rawInteger returns the raw value as an int64 or uint64, allowing for named
+// integer types such as models.RowStatus
+func rawInteger(raw interface{}) (i int64, u uint64, unsigned bool, err error) {
+	switch r := raw.(type) {
+	case time.Duration:
+		return int64(r / (10 * time.Millisecond)), 0, false, nil
+	case models.TimeStamp:
+		return 0, uint64(r), true, nil
+	}
+	rv := reflect.ValueOf(raw)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), 0, false, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return 0, rv.Uint(), true, nil
+	}
+	return 0, 0, false, fmt.Errorf("%w: %T is not an integer", models.ErrInvalidType, raw)
+}
+
+func (f fieldBinding) set(fv reflect.Value, value models.Value) error {
+	if value.Err != nil {
+		return value.Err
+	}
+	raw := value.Raw
+	switch f.kind {
+	case fieldValue:
+		fv.Set(reflect.ValueOf(value))
+		return nil
+	case fieldString:
+		var s string
+		switch f.node.Type.BaseType {
+		case types.BaseTypeEnum:
+			s = value.Label
+			if s == "" {
+				s = strconv.FormatInt(value.Int64(), 10)
+			}
+		case types.BaseTypeObjectIdentifier:
+			oid, err := models.ToOid(raw)
+			if err != nil {
+				return err
+			}
+			s = oid.String()
+		case types.BaseTypeBits:
+			s = strings.Join(value.Bits, " ")
+		default:
+			if isPlainOctetString(f.node.Type) || value.Format&models.FormatString == 0 {
+				s = string(value.Bytes())
+			} else {
+				s = strings.TrimSuffix(value.Formatted, " "+value.Units)
+			}
+		}
+		fv.SetString(s)
+		return nil
+	case fieldInt, fieldUint:
+		i, u, unsigned, err := rawInteger(raw)
+		if err != nil {
+			return err
+		}
+		if f.kind == fieldInt {
+			if unsigned {
+				i = int64(u)
+				if i < 0 {
+					return fmt.Errorf("%w: %d overflows %s", models.ErrOutOfRange, u, fv.Type())
+				}
+			}
+			if fv.OverflowInt(i) {
+				return fmt.Errorf("%w: %d overflows %s", models.ErrOutOfRange, i, fv.Type())
+			}
+			fv.SetInt(i)
+			return nil
+		}
+		if !unsigned {
+			if i < 0 {
+				return fmt.Errorf("%w: %d overflows %s", models.ErrOutOfRange, i, fv.Type())
+			}
+			u = uint64(i)
+		}
+		if fv.OverflowUint(u) {
+			return fmt.Errorf("%w: %d overflows %s", models.ErrOutOfRange, u, fv.Type())
+		}
+		fv.SetUint(u)
+		return nil
+	case fieldBool:
+		if b, ok := raw.(bool); ok {
+			fv.SetBool(b)
+			return nil
+		}
+	case fieldBytes:
+		bytes := append([]byte(nil), value.Bytes()...)
+		fv.Set(reflect.ValueOf(bytes).Convert(fv.Type()))
+		return nil
+	case fieldIP:
+		switch r := raw.(type) {
+		case []byte:
+			fv.Set(reflect.ValueOf(net.IP(append([]byte(nil), r...))))
+			return nil
+		case models.InetAddress:
+			fv.Set(reflect.ValueOf(r.IP))
+			return nil
+		}
+	case fieldDuration:
+		switch r := raw.(type) {
+		case time.Duration:
+			fv.SetInt(int64(r))
+			return nil
+		case models.TimeStamp:
+			fv.SetInt(int64(r.Duration()))
+			return nil
+		}
+	case fieldTime:
+		if t, ok := raw.(time.Time); ok {
+			fv.Set(reflect.ValueOf(t))
+			return nil
+		}
+	case fieldOid:
+		if oid, ok := raw.(types.Oid); ok {
+			fv.Set(reflect.ValueOf(oid))
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %T cannot be stored in %s", models.ErrInvalidType, raw, fv.Type())
+}
+
+// UnmarshalVarbinds stores the values of the varbinds in the fields of the
+// struct that v points to. Fields are bound to objects with tags such as
+// `snmp:"IF-MIB::ifDescr"`, and the Go type of each field is checked against
+// the type of its object when the struct type is first used. Varbinds for
+// objects without a field are ignored. Scalars must be for instance 0, and
+// all columns must be for the same instance, as the struct holds a single row.
+func UnmarshalVarbinds(varbinds []Varbind, v interface{}) error {
+	rv, b, err := structValue(v)
+	if err != nil {
+		return err
+	}
+	var rowInstance types.Oid
+	for _, vb := range varbinds {
+		fields := b.byOid[oidKey(vb.Node.Oid)]
+		if len(fields) == 0 {
+			continue
+		}
+		if vb.Node.Kind == types.NodeScalar {
+			if !vb.Instance.Equals(types.Oid{0}) {
+				return fmt.Errorf("Unmarshal %s: Scalar %s must have instance 0", vb.Oid, vb.Node.Name)
+			}
+		} else if rowInstance == nil {
+			if len(vb.Instance) == 0 {
+				return fmt.Errorf("Unmarshal %s: Column %s has no instance", vb.Oid, vb.Node.Name)
+			}
+			rowInstance = vb.Instance
+		} else if !vb.Instance.Equals(rowInstance) {
+			return fmt.Errorf("Unmarshal %s: Instance %s does not match %s of the previous columns", vb.Oid, vb.Instance, rowInstance)
+		}
+		for _, i := range fields {
+			f := b.fields[i]
+			if err := f.set(rv.Field(f.index), vb.Value); err != nil {
+				return fmt.Errorf("Unmarshal %s into %s: %w", vb.Oid, f.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Unmarshal stores the columns of the row in the fields of the struct that v
+// points to, as for UnmarshalVarbinds
+func (r TableRow) Unmarshal(v interface{}) error {
+	rv, b, err := structValue(v)
+	if err != nil {
+		return err
+	}
+	for _, f := range b.fields {
+		value, ok := r.Columns[f.node.Name]
+		if !ok {
+			continue
+		}
+		if err := f.set(rv.Field(f.index), value); err != nil {
+			return fmt.Errorf("Unmarshal %s.%s into %s: %w", f.node.Name, r.Instance, f.name, err)
+		}
+	}
+	return nil
+}
+
+// Unmarshal appends the rows to the slice of structs, or of pointers to
+// structs, that v points to
+func (t TableRows) Unmarshal(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("Cannot unmarshal into %T: Not a non-nil pointer to a slice", v)
+	}
+	slice := rv.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	for _, row := range t.Rows {
+		elem := reflect.New(elemType)
+		if err := row.Unmarshal(elem.Interface()); err != nil {
+			return err
+		}
+		if !isPtr {
+			elem = elem.Elem()
+		}
+		slice.Set(reflect.Append(slice, elem))
+	}
+	return nil
+}
+
+func (f fieldBinding) raw(fv reflect.Value) (interface{}, error) {
+	t := f.node.Type
+	switch f.kind {
+	case fieldValue:
+		return fv.Interface().(models.Value).Raw, nil
+	case fieldString:
+		if isPlainOctetString(t) {
+			return []byte(fv.String()), nil
+		}
+		return t.ParseValue(fv.String())
+	case fieldInt:
+		return fv.Int(), nil
+	case fieldUint:
+		if t.BaseType == types.BaseTypeUnsigned64 {
+			return fv.Uint(), nil
+		}
+		return models.ToInt64(fv.Uint())
+	case fieldBool:
+		if fv.Bool() {
+			return int64(models.TruthValueTrue), nil
+		}
+		return int64(models.TruthValueFalse), nil
+	case fieldBytes:
+		return append([]byte(nil), fv.Bytes()...), nil
+	case fieldIP:
+		ip := fv.Interface().(net.IP)
+		if ip4 := ip.To4(); ip4 != nil {
+			return []byte(ip4), nil
+		}
+		return []byte(ip), nil
+	case fieldDuration:
+		return int64(time.Duration(fv.Int()) / (10 * time.Millisecond)), nil
+	case fieldTime:
+		return models.EncodeDateAndTime(fv.Interface().(time.Time))
+	case fieldOid:
+		return fv.Interface().(types.Oid), nil
+	}
+	return nil, fmt.Errorf("%w: %s", models.ErrInvalidType, fv.Type())
+}
+
+// MarshalVarbinds returns the varbinds to SET the objects bound to the fields
+// of the struct, or pointer to struct, v. Columns are set for the given
+// instance, which must not be empty, and scalars for instance 0. Every value is validated against the
+// type of its object, and objects that are not writable are rejected with
+// ErrNotWritable. Fields tagged with omitempty are skipped if they have the
+// zero value.
+func MarshalVarbinds(v interface{}, instance types.Oid) ([]Varbind, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil, fmt.Errorf("Cannot marshal %T", v)
+	}
+	b, err := bindStruct(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+	varbinds := make([]Varbind, 0, len(b.fields))
+	for _, f := range b.fields {
+		fv := rv.Field(f.index)
+		if f.omitEmpty && fv.IsZero() {
+			continue
+		}
+		if f.node.Access != types.AccessReadWrite {
+			return nil, fmt.Errorf("Marshal %s: %w: %s is %s", f.name, ErrNotWritable, f.node.Name, f.node.Access)
+		}
+		raw, err := f.raw(fv)
+		if err == nil {
+			err = f.node.ValidateWrite(raw)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Marshal %s into %s: %w", f.name, f.node.Name, err)
+		}
+		vbInstance := instance
+		if f.node.Kind == types.NodeScalar {
+			vbInstance = types.Oid{0}
+		} else if len(vbInstance) == 0 {
+			return nil, fmt.Errorf("Marshal %s: Column %s has no instance", f.name, f.node.Name)
+		}
+		oid := make(types.Oid, 0, len(f.node.Oid)+len(vbInstance))
+		oid = append(append(oid, f.node.Oid...), vbInstance...)
+		varbinds = append(varbinds, Varbind{
+			Oid:      oid,
+			Node:     f.node,
+			Module:   f.node.GetModule().Name,
+			Kind:     f.node.Kind,
+			Instance: vbInstance,
+			Value:    f.node.Type.FormatValue(raw),
+			Units:    f.node.Type.Units,
+		})
+	}
+	return varbinds, nil
+}
@@ -0,0 +1,256 @@
+package gosmi_test
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+type testSystem struct {
+	Descr   string        `snmp:"SNMPv2-MIB::sysDescr"`
+	UpTime  time.Duration `snmp:"SNMPv2-MIB::sysUpTime"`
+	Contact string        `snmp:"sysContact"`
+	Ignored string
+}
+
+type testSettings struct {
+	Contact string    `snmp:"SNMPv2-MIB::sysContact"`
+	Name    string    `snmp:"SNMPv2-MIB::sysName,omitempty"`
+	Time    time.Time `snmp:"TEST-MIB::testTime,omitempty"`
+}
+
+type testTypes struct {
+	Enabled bool   `snmp:"TEST-MIB::testEnabled"`
+	Gateway net.IP `snmp:"TEST-MIB::testGateway"`
+	Server  net.IP `snmp:"TEST-MIB::testServer"`
+	Mac     []byte `snmp:"TEST-MIB::testMac"`
+}
+
+type testEntry struct {
+	Value  uint32 `snmp:"TEST-MIB::testEntryValue"`
+	Status string `snmp:"TEST-MIB::testEntryStatus,omitempty"`
+}
+
+func TestUnmarshalVarbindsScalars(t *testing.T) {
+	var system testSystem
+	err := gosmi.UnmarshalVarbinds(decodeVarbinds(t, map[string]interface{}{
+		"1.3.6.1.2.1.1.1.0": []byte("Test agent"),
+		"1.3.6.1.2.1.1.3.0": 8640000,
+		"1.3.6.1.2.1.1.4.0": []byte("admin@example.com"),
+		"1.3.6.1.2.1.1.5.0": []byte("unbound"),
+	}), &system)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := testSystem{Descr: "Test agent", UpTime: 24 * time.Hour, Contact: "admin@example.com"}
+	if system != want {
+		t.Errorf("UnmarshalVarbinds = %+v, want %+v", system, want)
+	}
+}
+
+func TestUnmarshalVarbindsRow(t *testing.T) {
+	var entry testEntry
+	err := gosmi.UnmarshalVarbinds(decodeVarbinds(t, map[string]interface{}{
+		"1.3.6.1.4.1.99999.1.10.1.3.1.97.1.3": 42,
+		"1.3.6.1.4.1.99999.1.10.1.4.1.97.1.3": 1,
+	}), &entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (testEntry{Value: 42, Status: "active"}); entry != want {
+		t.Errorf("UnmarshalVarbinds = %+v, want %+v", entry, want)
+	}
+}
+
+func TestUnmarshalVarbindsErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		values map[string]interface{}
+		target interface{}
+		err    error
+	}{
+		{"Scalar without instance 0", map[string]interface{}{"1.3.6.1.2.1.1.4.1": []byte("x")}, &testSystem{}, nil},
+		{"Column without instance", map[string]interface{}{"1.3.6.1.4.1.99999.1.10.1.3": 1}, &testEntry{}, nil},
+		{"Columns of different rows", map[string]interface{}{
+			"1.3.6.1.4.1.99999.1.10.1.3.1.97.1.3": 42,
+			"1.3.6.1.4.1.99999.1.10.1.4.1.98.1.3": 1,
+		}, &testEntry{}, nil},
+		{"Type mismatch", map[string]interface{}{"1.3.6.1.2.1.1.3.0": []byte("x")}, &testSystem{}, models.ErrInvalidType},
+		{"Counter64 overflows uint32", map[string]interface{}{"1.3.6.1.4.1.99999.1.4.0": uint64(1 << 32)}, &struct {
+			Big uint32 `snmp:"TEST-MIB::testBig"`
+		}{}, models.ErrOutOfRange},
+		{"Unsigned32 overflows int8", map[string]interface{}{"1.3.6.1.4.1.99999.1.10.1.3.1.97.1.3": 200}, &struct {
+			Value int8 `snmp:"TEST-MIB::testEntryValue"`
+		}{}, models.ErrOutOfRange},
+		{"Unsigned32 overflows uint8", map[string]interface{}{"1.3.6.1.4.1.99999.1.10.1.3.1.97.1.3": 300}, &struct {
+			Value uint8 `snmp:"TEST-MIB::testEntryValue"`
+		}{}, models.ErrOutOfRange},
+		{"Not a pointer", nil, testSystem{}, nil},
+	}
+	for _, test := range tests {
+		err := gosmi.UnmarshalVarbinds(decodeVarbinds(t, test.values), test.target)
+		if err == nil || (test.err != nil && !errors.Is(err, test.err)) {
+			t.Errorf("%s: got error %v, want %v", test.source, err, test.err)
+		}
+	}
+
+	bad := []struct {
+		source string
+		target interface{}
+	}{
+		{"int for DisplayString", &struct {
+			Descr int `snmp:"SNMPv2-MIB::sysDescr"`
+		}{}},
+		{"bool for DisplayString", &struct {
+			Descr bool `snmp:"SNMPv2-MIB::sysDescr"`
+		}{}},
+		{"net.IP for DisplayString", &struct {
+			Descr net.IP `snmp:"SNMPv2-MIB::sysDescr"`
+		}{}},
+		{"uint64 for an enumeration", &struct {
+			PeerType uint64 `snmp:"TEST-MIB::testEntryPeerType"`
+		}{}},
+		{"time.Duration for Unsigned32", &struct {
+			Value time.Duration `snmp:"TEST-MIB::testEntryValue"`
+		}{}},
+		{"int64 for Counter64", &struct {
+			Big int64 `snmp:"TEST-MIB::testBig"`
+		}{}},
+		{"[]byte for TimeTicks", &struct {
+			UpTime []byte `snmp:"SNMPv2-MIB::sysUpTime"`
+		}{}},
+		{"time.Time for DisplayString", &struct {
+			Name time.Time `snmp:"SNMPv2-MIB::sysName"`
+		}{}},
+	}
+	for _, test := range bad {
+		if err := gosmi.UnmarshalVarbinds(nil, test.target); err == nil {
+			t.Errorf("Binding %s: got no error", test.source)
+		}
+	}
+}
+
+func TestUnmarshalVarbindsTypes(t *testing.T) {
+	var got struct {
+		Big     uint64 `snmp:"TEST-MIB::testBig"`
+		Flags   []byte `snmp:"TEST-MIB::testFlags"`
+		Enabled bool   `snmp:"TEST-MIB::testEnabled"`
+		Gateway net.IP `snmp:"TEST-MIB::testGateway"`
+		Peer    net.IP `snmp:"TEST-MIB::testEntryPeer"`
+	}
+	err := gosmi.UnmarshalVarbinds(decodeVarbinds(t, map[string]interface{}{
+		"1.3.6.1.4.1.99999.1.4.0":             uint64(1<<64 - 1),
+		"1.3.6.1.4.1.99999.1.3.0":             []byte{0xa0, 0x40},
+		"1.3.6.1.4.1.99999.1.8.0":             1,
+		"1.3.6.1.4.1.99999.1.9.0":             []byte{192, 0, 2, 1},
+		"1.3.6.1.4.1.99999.1.10.1.6.1.97.1.3": []byte{0xfe, 0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 4},
+	}), &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Big != 1<<64-1 || !reflect.DeepEqual(got.Flags, []byte{0xa0, 0x40}) || !got.Enabled || !got.Gateway.Equal(net.IPv4(192, 0, 2, 1)) || !got.Peer.Equal(net.ParseIP("fe80::1")) {
+		t.Errorf("UnmarshalVarbinds = %+v", got)
+	}
+}
+
+func TestMarshalVarbinds(t *testing.T) {
+	varbinds, err := gosmi.MarshalVarbinds(testSettings{Contact: "admin@example.com"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(varbinds) != 1 || varbinds[0].String() != "SNMPv2-MIB::sysContact.0 = admin@example.com" {
+		t.Errorf("MarshalVarbinds with omitempty = %v, want only sysContact.0", varbinds)
+	}
+
+	instance := types.OidMustFromString("1.97.1.3")
+	varbinds, err = gosmi.MarshalVarbinds(&testEntry{Value: 42, Status: "createAndGo"}, instance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"TEST-MIB::testEntryValue.1.97.1.3 = 42", "TEST-MIB::testEntryStatus.1.97.1.3 = createAndGo(4)"}
+	if len(varbinds) != len(want) {
+		t.Fatalf("MarshalVarbinds of a row = %v, want %v", varbinds, want)
+	}
+	for i, vb := range varbinds {
+		if vb.String() != want[i] {
+			t.Errorf("Varbind %d = %q, want %q", i+1, vb, want[i])
+		}
+	}
+
+	var entry testEntry
+	if err := gosmi.UnmarshalVarbinds(varbinds, &entry); err != nil || entry.Value != 42 || entry.Status != "createAndGo" {
+		t.Errorf("Round trip = %+v, %v", entry, err)
+	}
+}
+
+func TestMarshalVarbindsErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		value  interface{}
+		err    error
+	}{
+		{"Read-only", testSystem{Descr: "Test agent"}, gosmi.ErrNotWritable},
+		{"Out of range", testEntry{Value: 101}, models.ErrOutOfRange},
+		{"Unknown enum", testEntry{Status: "bogus"}, nil},
+		{"Year out of range", testSettings{Time: time.Date(70000, 1, 1, 0, 0, 0, 0, time.UTC)}, models.ErrOutOfRange},
+	}
+	for _, test := range tests {
+		_, err := gosmi.MarshalVarbinds(test.value, types.Oid{1, 97, 1, 3})
+		if err == nil || (test.err != nil && !errors.Is(err, test.err)) {
+			t.Errorf("%s: got error %v, want %v", test.source, err, test.err)
+		}
+	}
+	for _, instance := range []types.Oid{nil, {}} {
+		if varbinds, err := gosmi.MarshalVarbinds(testEntry{Value: 42}, instance); err == nil {
+			t.Errorf("Column with instance %#v = %v, want error", instance, varbinds)
+		}
+	}
+}
+
+func TestMarshalVarbindsTypes(t *testing.T) {
+	tests := []struct {
+		source string
+		value  testTypes
+		want   []string
+	}{
+		{"IPv4", testTypes{Enabled: true, Gateway: net.IPv4(192, 0, 2, 1), Server: net.IPv4(192, 0, 2, 2), Mac: []byte{0x00, 0x00, 0x5e, 0x00, 0x53, 0x01}}, []string{
+			"TEST-MIB::testEnabled.0 = true(1)",
+			"TEST-MIB::testGateway.0 = 192.0.2.1",
+			"TEST-MIB::testServer.0 = 192.0.2.2",
+			"TEST-MIB::testMac.0 = 00:00:5e:00:53:01",
+		}},
+		{"IPv6", testTypes{Enabled: false, Gateway: net.IPv4(192, 0, 2, 1), Server: net.ParseIP("2001:db8::1"), Mac: []byte{0x00, 0x00, 0x5e, 0x00, 0x53, 0xff}}, []string{
+			"TEST-MIB::testEnabled.0 = false(2)",
+			"TEST-MIB::testGateway.0 = 192.0.2.1",
+			"TEST-MIB::testServer.0 = 2001:db8::1",
+			"TEST-MIB::testMac.0 = 00:00:5e:00:53:ff",
+		}},
+	}
+	for _, test := range tests {
+		varbinds, err := gosmi.MarshalVarbinds(test.value, nil)
+		if err != nil {
+			t.Errorf("%s: %v", test.source, err)
+			continue
+		}
+		if len(varbinds) != len(test.want) {
+			t.Errorf("%s: Varbinds = %v, want %v", test.source, varbinds, test.want)
+		}
+		for i, vb := range varbinds {
+			if i < len(test.want) && vb.String() != test.want[i] {
+				t.Errorf("%s: Varbind %d = %q, want %q", test.source, i+1, vb, test.want[i])
+			}
+		}
+		var got testTypes
+		if err := gosmi.UnmarshalVarbinds(varbinds, &got); err != nil {
+			t.Errorf("%s: Round trip: %v", test.source, err)
+		} else if got.Enabled != test.value.Enabled || !got.Gateway.Equal(test.value.Gateway) || !got.Server.Equal(test.value.Server) || !reflect.DeepEqual(got.Mac, test.value.Mac) {
+			t.Errorf("%s: Round trip = %+v, want %+v", test.source, got, test.value)
+		}
+	}
+}
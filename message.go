@@ -0,0 +1,334 @@
+package gosmi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+
+	"github.com/sleepinggenius2/gosmi/ber"
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// SnmpVersion is the version field of a community-based SNMP message
+type SnmpVersion int
+
+const (
+	SnmpVersion1  SnmpVersion = 0
+	SnmpVersion2c SnmpVersion = 1
+)
+
+func (v SnmpVersion) String() string {
+	switch v {
+	case SnmpVersion1:
+		return "SNMPv1"
+	case SnmpVersion2c:
+		return "SNMPv2c"
+	}
+	return fmt.Sprintf("SnmpVersion(%d)", int(v))
+}
+
+// PDUType is the context-specific tag of a PDU, as defined in RFC 3416
+type PDUType ber.Tag
+
+const (
+	PDUGetRequest     PDUType = 0xa0
+	PDUGetNextRequest PDUType = 0xa1
+	PDUResponse       PDUType = 0xa2
+	PDUSetRequest     PDUType = 0xa3
+	PDUTrap           PDUType = 0xa4 // SNMPv1 only
+	PDUGetBulkRequest PDUType = 0xa5
+	PDUInformRequest  PDUType = 0xa6
+	PDUSNMPv2Trap     PDUType = 0xa7
+	PDUReport         PDUType = 0xa8
+)
+
+var pduTypeNames = map[PDUType]string{
+	PDUGetRequest:     "GetRequest",
+	PDUGetNextRequest: "GetNextRequest",
+	PDUResponse:       "Response",
+	PDUSetRequest:     "SetRequest",
+	PDUTrap:           "Trap",
+	PDUGetBulkRequest: "GetBulkRequest",
+	PDUInformRequest:  "InformRequest",
+	PDUSNMPv2Trap:     "SNMPv2-Trap",
+	PDUReport:         "Report",
+}
+
+func (t PDUType) String() string {
+	if name, ok := pduTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("PDUType(0x%02x)", byte(t))
+}
+
+// ErrorStatus is the error-status of a PDU
+type ErrorStatus int
+
+var errorStatusNames = []string{
+	"noError",
+	"tooBig",
+	"noSuchName",
+	"badValue",
+	"readOnly",
+	"genErr",
+	"noAccess",
+	"wrongType",
+	"wrongLength",
+	"wrongEncoding",
+	"wrongValue",
+	"noCreation",
+	"inconsistentValue",
+	"resourceUnavailable",
+	"commitFailed",
+	"undoFailed",
+	"authorizationError",
+	"notWritable",
+	"inconsistentName",
+}
+
+func (e ErrorStatus) String() string {
+	if e >= 0 && int(e) < len(errorStatusNames) {
+		return errorStatusNames[e]
+	}
+	return fmt.Sprintf("ErrorStatus(%d)", int(e))
+}
+
+// GenericTrap is the generic-trap field of an SNMPv1 Trap-PDU
+type GenericTrap int
+
+const (
+	GenericTrapColdStart             GenericTrap = 0
+	GenericTrapWarmStart             GenericTrap = 1
+	GenericTrapLinkDown              GenericTrap = 2
+	GenericTrapLinkUp                GenericTrap = 3
+	GenericTrapAuthenticationFailure GenericTrap = 4
+	GenericTrapEgpNeighborLoss       GenericTrap = 5
+	GenericTrapEnterpriseSpecific    GenericTrap = 6
+)
+
+var genericTrapNames = []string{
+	"coldStart",
+	"warmStart",
+	"linkDown",
+	"linkUp",
+	"authenticationFailure",
+	"egpNeighborLoss",
+	"enterpriseSpecific",
+}
+
+func (g GenericTrap) String() string {
+	if g >= 0 && int(g) < len(genericTrapNames) {
+		return genericTrapNames[g]
+	}
+	return fmt.Sprintf("GenericTrap(%d)", int(g))
+}
+
+// PDU is a decoded PDU with its varbinds annotated through the loaded MIBs
+type PDU struct {
+	Type        PDUType
+	RequestID   int32
+	ErrorStatus ErrorStatus
+	ErrorIndex  int32
+
+	// Set instead of ErrorStatus and ErrorIndex for a GetBulkRequest
+	NonRepeaters   int32
+	MaxRepetitions int32
+
+	// Set instead of the request fields for an SNMPv1 Trap
+	Enterprise   types.Oid
+	AgentAddr    net.IP
+	GenericTrap  GenericTrap
+	SpecificTrap int32
+	Timestamp    uint32 // TimeTicks since the agent was last initialized
+
+	Varbinds []Varbind
+}
+
+// Message is a decoded community-based SNMPv1 or SNMPv2c message
+type Message struct {
+	Version   SnmpVersion
+	Community string
+	PDU       PDU
+}
+
+// ErrMalformedMessage is wrapped by every error returned for a message whose
+// structure cannot be decoded
+var ErrMalformedMessage = errors.New("Malformed SNMP message")
+
+func malformedMessage(field string, err error) error {
+	return fmt.Errorf("%w: %s: %v", ErrMalformedMessage, field, err)
+}
+
+// readValue decodes the next value of a message, which must have the
+// expected tag
+func readValue(data []byte, expected ber.Tag, field string) (interface{}, []byte, error) {
+	tag, raw, rest, err := ber.Decode(data)
+	if err != nil {
+		return nil, nil, malformedMessage(field, err)
+	}
+	if tag != expected {
+		return nil, nil, malformedMessage(field, fmt.Errorf("Expected %s, got %s", expected, tag))
+	}
+	return raw, rest, nil
+}
+
+func readInteger32(data []byte, field string) (int32, []byte, error) {
+	raw, rest, err := readValue(data, ber.TagInteger, field)
+	if err != nil {
+		return 0, nil, err
+	}
+	i := raw.(int64)
+	if i < math.MinInt32 || i > math.MaxInt32 {
+		return 0, nil, malformedMessage(field, fmt.Errorf("%d overflows 32 bits", i))
+	}
+	return int32(i), rest, nil
+}
+
+// DecodeMessage decodes an SNMPv1 or SNMPv2c message, such as the payload of
+// a captured UDP datagram, and annotates its varbinds. A varbind whose value
+// cannot be decoded is returned with Err set, and one whose value does not
+// match the type of its object with the Err of its Value set. An error in the
+// structure of the message is returned as an ErrMalformedMessage. On error,
+// the message holds the fields that were decoded before the error.
+func (d *VarbindDecoder) DecodeMessage(data []byte) (msg Message, err error) {
+	contents, rest, err := ber.ReadExpected(data, ber.TagSequence)
+	if err != nil {
+		return msg, malformedMessage("Message", err)
+	}
+	if len(rest) > 0 {
+		return msg, malformedMessage("Message", fmt.Errorf("%d trailing octets", len(rest)))
+	}
+	version, contents, err := readInteger32(contents, "Version")
+	if err != nil {
+		return
+	}
+	msg.Version = SnmpVersion(version)
+	if msg.Version != SnmpVersion1 && msg.Version != SnmpVersion2c {
+		return msg, malformedMessage("Version", fmt.Errorf("Unsupported SNMP version %d", version))
+	}
+	community, contents, err := readValue(contents, ber.TagOctetString, "Community")
+	if err != nil {
+		return
+	}
+	msg.Community = string(community.([]byte))
+	tag, pdu, rest, err := ber.ReadTLV(contents)
+	if err != nil {
+		return msg, malformedMessage("PDU", err)
+	}
+	if len(rest) > 0 {
+		return msg, malformedMessage("PDU", fmt.Errorf("%d trailing octets", len(rest)))
+	}
+	msg.PDU, err = d.decodePDU(PDUType(tag), pdu)
+	return
+}
+
+func (d *VarbindDecoder) decodePDU(pduType PDUType, data []byte) (pdu PDU, err error) {
+	pdu.Type = pduType
+	if _, ok := pduTypeNames[pduType]; !ok {
+		return pdu, malformedMessage("PDU", fmt.Errorf("Unknown PDU type 0x%02x", byte(pduType)))
+	}
+	if pduType == PDUTrap {
+		data, err = pdu.decodeTrapFields(data)
+	} else {
+		data, err = pdu.decodeRequestFields(data)
+	}
+	if err != nil {
+		return
+	}
+	list, rest, err := ber.ReadExpected(data, ber.TagSequence)
+	if err != nil {
+		return pdu, malformedMessage("Varbind list", err)
+	}
+	if len(rest) > 0 {
+		return pdu, malformedMessage("Varbind list", fmt.Errorf("%d trailing octets", len(rest)))
+	}
+	for len(list) > 0 {
+		var varbind []byte
+		if varbind, list, err = ber.ReadExpected(list, ber.TagSequence); err != nil {
+			return pdu, malformedMessage(fmt.Sprintf("Varbind %d", len(pdu.Varbinds)+1), err)
+		}
+		oid, value, err := readValue(varbind, ber.TagObjectIdentifier, fmt.Sprintf("Varbind %d name", len(pdu.Varbinds)+1))
+		if err != nil {
+			return pdu, err
+		}
+		pdu.Varbinds = append(pdu.Varbinds, d.decodeEncoded(oid.(types.Oid), value))
+	}
+	return
+}
+
+func (pdu *PDU) decodeRequestFields(data []byte) (rest []byte, err error) {
+	if pdu.RequestID, rest, err = readInteger32(data, "Request ID"); err != nil {
+		return
+	}
+	var first, second int32
+	if first, rest, err = readInteger32(rest, "Error status"); err != nil {
+		return
+	}
+	if second, rest, err = readInteger32(rest, "Error index"); err != nil {
+		return
+	}
+	if pdu.Type == PDUGetBulkRequest {
+		pdu.NonRepeaters, pdu.MaxRepetitions = first, second
+	} else {
+		pdu.ErrorStatus, pdu.ErrorIndex = ErrorStatus(first), second
+	}
+	return
+}
+
+func (pdu *PDU) decodeTrapFields(data []byte) (rest []byte, err error) {
+	enterprise, rest, err := readValue(data, ber.TagObjectIdentifier, "Enterprise")
+	if err != nil {
+		return
+	}
+	pdu.Enterprise = enterprise.(types.Oid)
+	agentAddr, rest, err := readValue(rest, ber.TagIpAddress, "Agent address")
+	if err != nil {
+		return
+	}
+	pdu.AgentAddr = net.IP(agentAddr.([]byte))
+	var genericTrap int32
+	if genericTrap, rest, err = readInteger32(rest, "Generic trap"); err != nil {
+		return
+	}
+	pdu.GenericTrap = GenericTrap(genericTrap)
+	if pdu.SpecificTrap, rest, err = readInteger32(rest, "Specific trap"); err != nil {
+		return
+	}
+	timestamp, rest, err := readValue(rest, ber.TagTimeTicks, "Timestamp")
+	if err != nil {
+		return
+	}
+	pdu.Timestamp = uint32(timestamp.(int64))
+	return
+}
+
+// decodeEncoded annotates a varbind with a BER-encoded value. The value is
+// formatted with the type of the object if its tag matches, and otherwise with
+// the type of its tag.
+func (d *VarbindDecoder) decodeEncoded(oid types.Oid, data []byte) Varbind {
+	tag, raw, rest, err := ber.Decode(data)
+	if err == nil && len(rest) > 0 {
+		err = fmt.Errorf("%w: %d trailing octets", ber.ErrMalformed, len(rest))
+	}
+	vb, lookupErr := d.Decode(oid, raw)
+	if lookupErr != nil {
+		vb = Varbind{Oid: oid, Err: lookupErr}
+	}
+	if err != nil {
+		vb.Value = models.Value{}
+		vb.Err = fmt.Errorf("Decode value of %s: %w", oid, err)
+		return vb
+	}
+	if expected, tagErr := ber.TypeTag(vb.Node.Type); lookupErr != nil || tagErr != nil || expected != tag {
+		vb.Value, _, _ = ber.DecodeValue(data, vb.Node.Type, d.flags...)
+	}
+	return vb
+}
+
+// DecodeMessage decodes a single message without caching. Use a
+// VarbindDecoder to decode many messages.
+func DecodeMessage(data []byte, flags ...models.Format) (Message, error) {
+	return NewVarbindDecoder(flags...).DecodeMessage(data)
+}
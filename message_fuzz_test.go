@@ -0,0 +1,35 @@
+//go:build go1.18
+// +build go1.18
+
+package gosmi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/ber"
+)
+
+func FuzzDecodeMessage(f *testing.F) {
+	for _, payload := range messagePayloads {
+		f.Add(decodePayload(f, payload))
+	}
+	d := gosmi.NewVarbindDecoder()
+	f.Fuzz(func(t *testing.T, data []byte) {
+		msg, err := d.DecodeMessage(data)
+		if err != nil {
+			if !errors.Is(err, gosmi.ErrMalformedMessage) {
+				t.Fatalf("Error does not wrap ErrMalformedMessage: %v", err)
+			}
+			return
+		}
+		for i, vb := range msg.PDU.Varbinds {
+			// Only a value that could not be decoded is left unformatted
+			if vb.Value.Raw == nil && vb.Value.Formatted == "" && !errors.Is(vb.Err, ber.ErrMalformed) {
+				t.Errorf("Varbind %d: got error %v for an unformatted value, want ErrMalformed", i+1, vb.Err)
+			}
+			_ = vb.String()
+		}
+	})
+}
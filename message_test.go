@@ -0,0 +1,184 @@
+package gosmi_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/ber"
+	"github.com/sleepinggenius2/gosmi/models"
+)
+
+// Payloads of captured UDP datagrams
+const (
+	// SNMPv1 linkDown Trap from agent 192.0.2.1 of enterprise
+	// NET-SNMP-MIB::netSnmpAgentOIDs.10, with ifIndex, ifAdminStatus and
+	// ifOperStatus
+	v1TrapPayload = "305e02010004067075626c6963a451060a2b06010401bf0803020a4004c00002010201020201004302303930" +
+		"33300f060a2b060102010202010103020103300f060a2b060102010202010703020101300f060a2b060102010202010803020102"
+	// SNMPv2c Response with a Counter64, an ifOperStatus encoded as a Gauge32
+	// and a noSuchInstance exception
+	v2cResponsePayload = "307602010104067075626c6963a26902046b8b4567020100020100305b301006082b0601020101030043040083d6" +
+		"00300f060a2b0601020102020108030201023015060b2b060102011f01010106034606010000000000300f060a2b0601020102020108" +
+		"04420102300e060a2b0601020102020102058100"
+	// SNMPv2c Response with error-status notWritable for the first varbind
+	v2cErrorPayload = "302702010104067075626c6963a21a02012a020111020101300f300d06082b06010201010300430100"
+	// SNMPv2c GetBulkRequest with one non-repeater and ten repetitions
+	v2cGetBulkPayload = "303402010104067075626c6963a52702010702010102010a301c300b06072b0601020101030500300d06092b060102010202010205" +
+		"00"
+	// SNMPv2c Report of SNMP-USER-BASED-SM-MIB::usmStatsUnknownEngineIDs.0,
+	// which is not loaded
+	v2cReportPayload = "302902010104067075626c6963a81c0201630201000201003011300f060a2b060106030f01010400410103"
+)
+
+var messagePayloads = []string{v1TrapPayload, v2cResponsePayload, v2cErrorPayload, v2cGetBulkPayload, v2cReportPayload}
+
+func decodePayload(t testing.TB, payload string) []byte {
+	data, err := hex.DecodeString(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+var messageTests = []struct {
+	source      string
+	payload     string
+	version     gosmi.SnmpVersion
+	pduType     gosmi.PDUType
+	requestID   int32
+	errorStatus gosmi.ErrorStatus
+	errorIndex  int32
+	varbinds    []string
+}{
+	{"Trap", v1TrapPayload, gosmi.SnmpVersion1, gosmi.PDUTrap, 0, gosmi.ErrorStatus(0), 0, []string{
+		"IF-MIB::ifIndex.3 = 3",
+		"IF-MIB::ifAdminStatus.3 = up(1)",
+		"IF-MIB::ifOperStatus.3 = down(2)",
+	}},
+	{"Response", v2cResponsePayload, gosmi.SnmpVersion2c, gosmi.PDUResponse, 1804289383, gosmi.ErrorStatus(0), 0, []string{
+		"SNMPv2-MIB::sysUpTime.0 = 1d 0h 0m",
+		"IF-MIB::ifOperStatus.3 = down(2)",
+		"IF-MIB::ifHCInOctets.3 = 1099511627776",
+		"IF-MIB::ifOperStatus.4 = 2",
+		"IF-MIB::ifDescr.5 = noSuchInstance",
+	}},
+	{"Response with error", v2cErrorPayload, gosmi.SnmpVersion2c, gosmi.PDUResponse, 42, gosmi.ErrorStatus(17), 1, []string{
+		"SNMPv2-MIB::sysUpTime.0 = 0s",
+	}},
+	{"GetBulkRequest", v2cGetBulkPayload, gosmi.SnmpVersion2c, gosmi.PDUGetBulkRequest, 7, gosmi.ErrorStatus(0), 0, []string{
+		"SNMPv2-MIB::sysUpTime = NULL",
+		"IF-MIB::ifDescr = NULL",
+	}},
+	{"Report", v2cReportPayload, gosmi.SnmpVersion2c, gosmi.PDUReport, 99, gosmi.ErrorStatus(0), 0, []string{
+		"SNMPv2-SMI::snmpModules.15.1.1.4.0 = 3",
+	}},
+}
+
+func TestDecodeMessage(t *testing.T) {
+	d := gosmi.NewVarbindDecoder()
+	for _, test := range messageTests {
+		msg, err := d.DecodeMessage(decodePayload(t, test.payload))
+		if err != nil {
+			t.Errorf("%s: DecodeMessage: %v", test.source, err)
+			continue
+		}
+		if msg.Version != test.version || msg.Community != "public" || msg.PDU.Type != test.pduType {
+			t.Errorf("%s: got %s %q %s, want %s \"public\" %s", test.source, msg.Version, msg.Community, msg.PDU.Type, test.version, test.pduType)
+		}
+		pdu := msg.PDU
+		if pdu.RequestID != test.requestID || pdu.ErrorStatus != test.errorStatus || pdu.ErrorIndex != test.errorIndex {
+			t.Errorf("%s: got request ID %d, error %s at %d, want %d, %s at %d", test.source, pdu.RequestID, pdu.ErrorStatus, pdu.ErrorIndex, test.requestID, test.errorStatus, test.errorIndex)
+		}
+		if len(pdu.Varbinds) != len(test.varbinds) {
+			t.Errorf("%s: got %d varbinds, want %d", test.source, len(pdu.Varbinds), len(test.varbinds))
+			continue
+		}
+		for i, vb := range pdu.Varbinds {
+			if s := vb.String(); s != test.varbinds[i] {
+				t.Errorf("%s: varbind %d = %q, want %q", test.source, i+1, s, test.varbinds[i])
+			}
+		}
+	}
+}
+
+func TestDecodeMessageFields(t *testing.T) {
+	msg, err := gosmi.DecodeMessage(decodePayload(t, v1TrapPayload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdu := msg.PDU
+	if pdu.Enterprise.String() != "1.3.6.1.4.1.8072.3.2.10" || pdu.AgentAddr.String() != "192.0.2.1" {
+		t.Errorf("Trap enterprise %s from %s, want 1.3.6.1.4.1.8072.3.2.10 from 192.0.2.1", pdu.Enterprise, pdu.AgentAddr)
+	}
+	if pdu.GenericTrap != gosmi.GenericTrapLinkDown || pdu.SpecificTrap != 0 || pdu.Timestamp != 12345 {
+		t.Errorf("Trap is %s/%d at %d, want linkDown/0 at 12345", pdu.GenericTrap, pdu.SpecificTrap, pdu.Timestamp)
+	}
+
+	msg, err = gosmi.DecodeMessage(decodePayload(t, v2cGetBulkPayload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.PDU.NonRepeaters != 1 || msg.PDU.MaxRepetitions != 10 {
+		t.Errorf("GetBulkRequest has %d non-repeaters and %d repetitions, want 1 and 10", msg.PDU.NonRepeaters, msg.PDU.MaxRepetitions)
+	}
+}
+
+func TestDecodeMessageVarbindErrors(t *testing.T) {
+	msg, err := gosmi.DecodeMessage(decodePayload(t, v2cResponsePayload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	varbinds := msg.PDU.Varbinds
+
+	// Wrong tag
+	wrongTag := varbinds[3]
+	if wrongTag.Err != nil || !errors.Is(wrongTag.Value.Err, models.ErrInvalidType) {
+		t.Errorf("Gauge32 ifOperStatus: got errors %v and %v, want nil and ErrInvalidType", wrongTag.Err, wrongTag.Value.Err)
+	}
+	if wrongTag.Value.Raw != int64(2) || wrongTag.Value.Label != "" {
+		t.Errorf("Gauge32 ifOperStatus: got %#v labelled %q, want int64(2) without label", wrongTag.Value.Raw, wrongTag.Value.Label)
+	}
+
+	// Exception
+	exception := varbinds[4]
+	if exception.Err != nil || exception.Value.Err != nil || exception.Value.Raw != ber.NoSuchInstance {
+		t.Errorf("noSuchInstance: got %#v with errors %v and %v", exception.Value.Raw, exception.Err, exception.Value.Err)
+	}
+
+	// Unknown OID
+	msg, err = gosmi.DecodeMessage(decodePayload(t, v2cReportPayload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknown := msg.PDU.Varbinds[0]
+	if unknown.Node.Type != nil || unknown.Value.Raw != int64(3) || unknown.Value.Err != nil {
+		t.Errorf("Unknown OID: got %#v with type %v and error %v, want int64(3) formatted by its tag", unknown.Value.Raw, unknown.Node.Type, unknown.Value.Err)
+	}
+
+	// Malformed value in a well-formed varbind
+	data := decodePayload(t, v2cErrorPayload)
+	data[len(data)-2], data[len(data)-1] = 0x02, 0x00
+	msg, err = gosmi.DecodeMessage(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if malformed := msg.PDU.Varbinds[0]; !errors.Is(malformed.Err, ber.ErrMalformed) {
+		t.Errorf("Empty INTEGER: got error %v, want ErrMalformed", malformed.Err)
+	}
+}
+
+func TestDecodeMessageMalformed(t *testing.T) {
+	for _, payload := range messagePayloads {
+		data := decodePayload(t, payload)
+		for length := 0; length < len(data); length++ {
+			if _, err := gosmi.DecodeMessage(data[:length]); !errors.Is(err, gosmi.ErrMalformedMessage) {
+				t.Errorf("Truncated to %d octets: got error %v, want ErrMalformedMessage", length, err)
+			}
+		}
+		if _, err := gosmi.DecodeMessage(append(data, 0)); !errors.Is(err, gosmi.ErrMalformedMessage) {
+			t.Errorf("Trailing octet: got error %v, want ErrMalformedMessage", err)
+		}
+	}
+}
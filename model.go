@@ -0,0 +1,96 @@
+package gosmi
+
+import (
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/smi"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+func (n SmiNode) AsBaseNode() models.BaseNode {
+	return models.BaseNode{
+		Name:         n.Name,
+		Oid:          n.Oid,
+		OidFormatted: RenderOID(n.Oid, OidFormatQualified),
+		OidLen:       uint(n.OidLen),
+	}
+}
+
+func (n SmiNode) AsScalarNode() models.ScalarNode {
+	node := models.ScalarNode{
+		BaseNode: n.AsBaseNode(),
+	}
+	if n.Type != nil {
+		node.Type = *n.Type
+	}
+	return node
+}
+
+func (n SmiNode) AsColumnNode() models.ColumnNode {
+	return models.ColumnNode(n.AsScalarNode())
+}
+
+func columnNodes(nodes []SmiNode) []models.ColumnNode {
+	if nodes == nil {
+		return nil
+	}
+	columns := make([]models.ColumnNode, len(nodes))
+	for i, node := range nodes {
+		columns[i] = node.AsColumnNode()
+	}
+	return columns
+}
+
+func (n SmiNode) AsRowNode() models.RowNode {
+	smiRow := n.getRow()
+	if smiRow == nil {
+		return models.RowNode{}
+	}
+	row := CreateNode(smiRow)
+	columns, columnOrder := row.GetColumns()
+	node := models.RowNode{
+		BaseNode: row.AsBaseNode(),
+		Columns:  make([]models.ColumnNode, len(columnOrder)),
+		Implied:  row.GetImplied(),
+		Index:    columnNodes(row.GetIndex()),
+	}
+	for i, name := range columnOrder {
+		node.Columns[i] = columns[name].AsColumnNode()
+	}
+	return node
+}
+
+func (n SmiNode) AsTableNode() models.TableNode {
+	table := n
+	if n.Kind != types.NodeTable {
+		smiRow := n.getRow()
+		if smiRow == nil {
+			return models.TableNode{}
+		}
+		smiTable := smi.GetParentNode(smiRow)
+		if smiTable == nil || smiTable.NodeKind != types.NodeTable {
+			return models.TableNode{}
+		}
+		table = CreateNode(smiTable)
+	}
+	return models.TableNode{
+		BaseNode: table.AsBaseNode(),
+		Row:      table.AsRowNode(),
+	}
+}
+
+func (n SmiNode) AsNotificationNode() models.NotificationNode {
+	return n.AsNotification().AsNotificationNode()
+}
+
+func (n Notification) AsNotificationNode() models.NotificationNode {
+	node := models.NotificationNode{
+		BaseNode: n.AsBaseNode(),
+	}
+	if n.Objects != nil {
+		node.Objects = make([]models.ScalarNode, len(n.Objects))
+		for i, object := range n.Objects {
+			node.Objects[i] = object.AsScalarNode()
+		}
+	}
+	return node
+}
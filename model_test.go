@@ -0,0 +1,106 @@
+package gosmi_test
+
+import (
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi/models"
+)
+
+func columnNames(columns []models.ColumnNode) []string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+	}
+	return names
+}
+
+func TestAsBaseNode(t *testing.T) {
+	tests := []struct {
+		node      string
+		formatted string
+		oidLen    uint
+	}{
+		{"sysDescr", "SNMPv2-MIB::sysDescr", 8},
+		{"ifDescr", "IF-MIB::ifDescr", 10},
+		{"testEntryValue", "TEST-MIB::testEntryValue", 11},
+	}
+	for _, test := range tests {
+		node := getNode(t, test.node).AsBaseNode()
+		if node.Name != test.node || node.OidFormatted != test.formatted || node.OidLen != test.oidLen || uint(len(node.Oid)) != test.oidLen {
+			t.Errorf("%s: AsBaseNode = %+v, want %s with %d sub-identifiers", test.node, node, test.formatted, test.oidLen)
+		}
+	}
+}
+
+func TestAsScalarNode(t *testing.T) {
+	scalar := getNode(t, "sysDescr").AsScalarNode()
+	if scalar.Type.Name != "DisplayString" || len(scalar.Type.Ranges) != 1 || scalar.Type.Ranges[0].String() != "0..255" {
+		t.Errorf("sysDescr: AsScalarNode has type %s with ranges %v, want DisplayString 0..255", scalar.Type.Name, scalar.Type.Ranges)
+	}
+	column := getNode(t, "ifOperStatus").AsColumnNode()
+	if column.OidFormatted != "IF-MIB::ifOperStatus" || column.Type.Enum == nil || column.Type.Enum.Name(2) != "down" {
+		t.Errorf("ifOperStatus: AsColumnNode = %+v", column)
+	}
+}
+
+func TestAsRowNode(t *testing.T) {
+	tests := []struct {
+		node    string
+		row     string
+		columns []string
+		index   []string
+		implied bool
+	}{
+		{"ifEntry", "ifEntry", []string{"ifIndex", "ifDescr", "ifMtu", "ifSpeed", "ifPhysAddress", "ifAdminStatus", "ifOperStatus", "ifLastChange", "ifInOctets"}, []string{"ifIndex"}, false},
+		{"ifTable", "ifEntry", nil, []string{"ifIndex"}, false},
+		{"ifName", "ifXEntry", []string{"ifName", "ifHCInOctets", "ifAlias"}, []string{"ifIndex"}, false},
+		{"testEntryValue", "testEntry", nil, []string{"testEntryName", "testEntryOid"}, true},
+		{"sysDescr", "", nil, nil, false},
+	}
+	for _, test := range tests {
+		row := getNode(t, test.node).AsRowNode()
+		if row.Name != test.row || row.Implied != test.implied {
+			t.Errorf("%s: AsRowNode = %s, implied %t, want %s, implied %t", test.node, row.Name, row.Implied, test.row, test.implied)
+		}
+		if test.columns != nil && !equalStrings(columnNames(row.Columns), test.columns) {
+			t.Errorf("%s: AsRowNode has columns %v, want %v", test.node, columnNames(row.Columns), test.columns)
+		}
+		if index := columnNames(row.Index); !equalStrings(index, test.index) {
+			t.Errorf("%s: AsRowNode has index %v, want %v", test.node, index, test.index)
+		}
+	}
+}
+
+func TestAsTableNode(t *testing.T) {
+	for _, name := range []string{"testTable", "testEntry", "testEntryValue"} {
+		table := getNode(t, name).AsTableNode()
+		if table.Name != "testTable" || table.OidFormatted != "TEST-MIB::testTable" || table.Row.Name != "testEntry" || !table.Implied() {
+			t.Errorf("%s: AsTableNode = %s with row %s", name, table.Name, table.Row.Name)
+			continue
+		}
+		index, err := table.BuildIndex("a", "1.3")
+		if err != nil || index.String() != "1.97.1.3" {
+			t.Errorf("%s: BuildIndex = %s, %v, want 1.97.1.3", name, index, err)
+		}
+	}
+	if table := getNode(t, "sysDescr").AsTableNode(); table.Name != "" || len(table.Columns()) != 0 {
+		t.Errorf("sysDescr: AsTableNode = %+v, want none", table)
+	}
+}
+
+func TestAsNotificationNode(t *testing.T) {
+	notification := getNode(t, "linkDown").AsNotificationNode()
+	if notification.OidFormatted != "IF-MIB::linkDown" {
+		t.Errorf("linkDown: AsNotificationNode = %+v", notification.BaseNode)
+	}
+	objects := make([]string, len(notification.Objects))
+	for i, object := range notification.Objects {
+		objects[i] = object.Name
+		if object.Type.Name == "" {
+			t.Errorf("linkDown: object %s has no type", object.Name)
+		}
+	}
+	if want := []string{"ifIndex", "ifAdminStatus", "ifOperStatus"}; !equalStrings(objects, want) {
+		t.Errorf("linkDown: AsNotificationNode has objects %v, want %v", objects, want)
+	}
+}
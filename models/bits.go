@@ -0,0 +1,162 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// BitsPadding selects the length of an encoded BITS value
+type BitsPadding byte
+
+const (
+	BitsPaddingDeclared BitsPadding = iota // Long enough for every named bit, as RFC 3417 requires
+	BitsPaddingMinimal                     // Only long enough for the bits that are set
+)
+
+func (t Type) checkBits() error {
+	if t.BaseType != types.BaseTypeBits {
+		return fmt.Errorf("%w: %s is not a BITS type", ErrInvalidType, t.BaseType)
+	}
+	return nil
+}
+
+// declaredBitsLength returns the number of octets needed for all of the named
+// bits
+func (t Type) declaredBitsLength() int {
+	if t.Enum == nil {
+		return 0
+	}
+	var highest int64 = -1
+	for _, namedNumber := range t.Enum.Values {
+		if namedNumber.Value > highest {
+			highest = namedNumber.Value
+		}
+	}
+	return int(highest/8 + 1)
+}
+
+// PadBits returns the octets of the BITS value with the given padding, which
+// defaults to BitsPaddingDeclared. Bits that are set are never dropped, even
+// if they are not named.
+func (t Type) PadBits(octets []byte, padding ...BitsPadding) []byte {
+	length := len(octets)
+	for length > 0 && octets[length-1] == 0 {
+		length--
+	}
+	if len(padding) == 0 || padding[0] == BitsPaddingDeclared {
+		if declared := t.declaredBitsLength(); declared > length {
+			length = declared
+		}
+	}
+	out := make([]byte, length)
+	copy(out, octets)
+	return out
+}
+
+func setBit(octets []byte, bit int64) []byte {
+	for int64(len(octets)) <= bit/8 {
+		octets = append(octets, 0)
+	}
+	octets[bit/8] |= 0x80 >> uint(bit%8)
+	return octets
+}
+
+func clearBit(octets []byte, bit int64) {
+	if bit/8 < int64(len(octets)) {
+		octets[bit/8] &^= 0x80 >> uint(bit%8)
+	}
+}
+
+func (t Type) bitPosition(name string) (int64, error) {
+	bit, err := t.Enum.Value(name)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownBit, name)
+	}
+	return bit, nil
+}
+
+// BitsFromNames encodes the named bits as a BITS value, as defined in RFC 2578
+// section 7.1.4
+func (t Type) BitsFromNames(names []string, padding ...BitsPadding) ([]byte, error) {
+	return t.UpdateBits(nil, names, nil, padding...)
+}
+
+// BitsFromPositions encodes the bit positions as a BITS value. Positions that
+// are not named in the enumeration are errors.
+func (t Type) BitsFromPositions(positions []int, padding ...BitsPadding) ([]byte, error) {
+	if err := t.checkBits(); err != nil {
+		return nil, err
+	}
+	var octets []byte
+	for _, position := range positions {
+		if _, ok := t.Enum.lookup(int64(position)); !ok {
+			return nil, fmt.Errorf("%w: position %d", ErrUnknownBit, position)
+		}
+		octets = setBit(octets, int64(position))
+	}
+	return t.PadBits(octets, padding...), nil
+}
+
+// BitsFromDefval encodes the value of a DEFVAL clause for a BITS object, such
+// as "{ a, b }" or "{}"
+func (t Type) BitsFromDefval(defval string, padding ...BitsPadding) ([]byte, error) {
+	s := strings.TrimSpace(defval)
+	if !strings.HasPrefix(s, "{") || !strings.HasSuffix(s, "}") {
+		return nil, fmt.Errorf("Invalid BITS DEFVAL %q", defval)
+	}
+	names := strings.FieldsFunc(s[1:len(s)-1], func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\r' || r == '\n'
+	})
+	return t.BitsFromNames(names, padding...)
+}
+
+// UpdateBits sets and clears the named bits in a BITS value. Other bits are
+// preserved, including those that are not named in the enumeration.
+func (t Type) UpdateBits(octets []byte, set []string, clear []string, padding ...BitsPadding) ([]byte, error) {
+	if err := t.checkBits(); err != nil {
+		return nil, err
+	}
+	out := append([]byte(nil), octets...)
+	for _, name := range set {
+		bit, err := t.bitPosition(name)
+		if err != nil {
+			return nil, err
+		}
+		out = setBit(out, bit)
+	}
+	for _, name := range clear {
+		bit, err := t.bitPosition(name)
+		if err != nil {
+			return nil, err
+		}
+		clearBit(out, bit)
+	}
+	return t.PadBits(out, padding...), nil
+}
+
+// BitPositions returns the positions of the bits that are set in a BITS value
+func BitPositions(octets []byte) (positions []int) {
+	for i, octet := range octets {
+		for j := 0; j < 8; j++ {
+			if octet&(0x80>>uint(j)) != 0 {
+				positions = append(positions, 8*i+j)
+			}
+		}
+	}
+	return
+}
+
+// BitsToNames returns the names of the bits that are set in a BITS value, and
+// separately the positions of the set bits that are not named
+func (t Type) BitsToNames(octets []byte) (names []string, unknown []int) {
+	for _, position := range BitPositions(octets) {
+		if name, ok := t.Enum.lookup(int64(position)); ok {
+			names = append(names, name)
+		} else {
+			unknown = append(unknown, position)
+		}
+	}
+	return
+}
@@ -0,0 +1,126 @@
+package models_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// bitsType has named bits in the first two octets, as in
+// { a(0), b(1), c(9) }
+var bitsType = models.Type{
+	BaseType: types.BaseTypeBits,
+	Enum: &models.Enum{
+		BaseType: types.BaseTypeBits,
+		Values:   []models.NamedNumber{{Name: "a", Value: 0}, {Name: "b", Value: 1}, {Name: "c", Value: 9}},
+	},
+}
+
+func TestPadBits(t *testing.T) {
+	for _, test := range []struct {
+		source  string
+		octets  []byte
+		padding []models.BitsPadding
+		padded  []byte
+	}{
+		{"Declared by default", []byte{0x80}, nil, []byte{0x80, 0x00}},
+		{"Declared", []byte{0x80, 0x00, 0x00}, []models.BitsPadding{models.BitsPaddingDeclared}, []byte{0x80, 0x00}},
+		{"Minimal", []byte{0x80, 0x00, 0x00}, []models.BitsPadding{models.BitsPaddingMinimal}, []byte{0x80}},
+		{"Minimal empty", []byte{0x00}, []models.BitsPadding{models.BitsPaddingMinimal}, []byte{}},
+		{"Unnamed bits are kept", []byte{0x00, 0x00, 0x01}, []models.BitsPadding{models.BitsPaddingDeclared}, []byte{0x00, 0x00, 0x01}},
+	} {
+		if padded := bitsType.PadBits(test.octets, test.padding...); !bytes.Equal(padded, test.padded) {
+			t.Errorf("%s: PadBits(% x) = % x, want % x", test.source, test.octets, padded, test.padded)
+		}
+	}
+}
+
+func TestUpdateBits(t *testing.T) {
+	// Bit 2 and bit 23 are not named
+	octets := []byte{0xa0, 0x00, 0x01}
+	updated, err := bitsType.UpdateBits(octets, []string{"c"}, []string{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{0x20, 0x40, 0x01}; !bytes.Equal(updated, want) {
+		t.Errorf("UpdateBits = % x, want % x", updated, want)
+	}
+	if octets[0] != 0xa0 {
+		t.Errorf("UpdateBits modified its argument to % x", octets)
+	}
+	names, unknown := bitsType.BitsToNames(updated)
+	if !reflect.DeepEqual(names, []string{"c"}) || !reflect.DeepEqual(unknown, []int{2, 23}) {
+		t.Errorf("BitsToNames = %v, %v, want [c], [2 23]", names, unknown)
+	}
+	if _, err := bitsType.UpdateBits(octets, []string{"d"}, nil); !errors.Is(err, models.ErrUnknownBit) {
+		t.Errorf("UpdateBits with an unknown name: got error %v, want ErrUnknownBit", err)
+	}
+}
+
+func TestBitsFromDefval(t *testing.T) {
+	for defval, want := range map[string][]byte{
+		"{}":       {0x00, 0x00},
+		"{ }":      {0x00, 0x00},
+		"{ a, c }": {0x80, 0x40},
+		"{b}":      {0x40, 0x00},
+	} {
+		if octets, err := bitsType.BitsFromDefval(defval); err != nil || !bytes.Equal(octets, want) {
+			t.Errorf("BitsFromDefval(%q) = % x, %v, want % x", defval, octets, err, want)
+		}
+	}
+	if octets, err := bitsType.BitsFromDefval("{}", models.BitsPaddingMinimal); err != nil || len(octets) != 0 {
+		t.Errorf("BitsFromDefval(\"{}\") with minimal padding = % x, %v, want no octets", octets, err)
+	}
+	if _, err := bitsType.BitsFromDefval("a, b"); err == nil {
+		t.Error("BitsFromDefval without braces gave no error")
+	}
+}
+
+func TestBitsFormatAndParse(t *testing.T) {
+	octets := []byte{0xc0, 0x40, 0x01}
+	v := models.GetEnumBitsFormatted(octets, models.FormatEnumName, bitsType.Enum)
+	if v.Formatted != "a b c unknown(23)" || !reflect.DeepEqual(v.Bits, []string{"a", "b", "c"}) {
+		t.Errorf("GetEnumBitsFormatted = %q with bits %v", v.Formatted, v.Bits)
+	}
+	parsed, err := models.ParseBits(v.Formatted, bitsType.Enum)
+	if err != nil || !bytes.Equal(parsed, octets) {
+		t.Errorf("ParseBits(%q) = % x, %v, want % x", v.Formatted, parsed, err, octets)
+	}
+}
+
+func TestBitsFromPositions(t *testing.T) {
+	for _, test := range []struct {
+		source    string
+		positions []int
+		padding   []models.BitsPadding
+		octets    []byte
+	}{
+		{"Valid", []int{0, 9}, nil, []byte{0x80, 0x40}},
+		{"Valid with minimal padding", []int{1}, []models.BitsPadding{models.BitsPaddingMinimal}, []byte{0x40}},
+		{"None", nil, nil, []byte{0x00, 0x00}},
+	} {
+		octets, err := bitsType.BitsFromPositions(test.positions, test.padding...)
+		if err != nil || !bytes.Equal(octets, test.octets) {
+			t.Errorf("%s: BitsFromPositions(%v) = % x, %v, want % x", test.source, test.positions, octets, err, test.octets)
+			continue
+		}
+		if positions := models.BitPositions(octets); len(positions) != len(test.positions) || (len(positions) > 0 && !reflect.DeepEqual(positions, test.positions)) {
+			t.Errorf("%s: BitPositions(% x) = %v, want %v", test.source, octets, positions, test.positions)
+		}
+	}
+	for _, test := range []struct {
+		source    string
+		positions []int
+	}{
+		{"Unnamed", []int{0, 2}},
+		{"Negative", []int{-1}},
+	} {
+		if octets, err := bitsType.BitsFromPositions(test.positions); !errors.Is(err, models.ErrUnknownBit) {
+			t.Errorf("%s: BitsFromPositions(%v) = % x, %v, want ErrUnknownBit", test.source, test.positions, octets, err)
+		}
+	}
+}
@@ -0,0 +1,603 @@
+package models
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// StringHint is a single octet-format of an OCTET STRING DISPLAY-HINT
+type StringHint struct {
+	Repeat     bool
+	Length     int
+	Kind       byte // One of a, t, d, o or x
+	Separator  byte
+	Terminator byte
+	Numeric    bool
+}
+
+const (
+	StateRepeat = iota
+	StateLength
+	StateFormat
+	StateSeparator
+	StateTerminator
+	StateEnd
+)
+
+// DisplayHint is a compiled OCTET STRING DISPLAY-HINT, as defined in RFC 2579
+// section 3.1. The last octet-format is repeated until the value is exhausted.
+// A DisplayHint without any octet-formats displays the value as hexadecimal
+// octets.
+type DisplayHint struct {
+	hint  string
+	specs []StringHint
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func CompileDisplayHint(hint string) (*DisplayHint, error) {
+	h := &DisplayHint{hint: hint}
+	if hint == "" {
+		return h, nil
+	}
+	var (
+		curr      StringHint
+		hasLength bool
+	)
+	state := StateRepeat
+	for i := 0; i < len(hint); {
+		c := hint[i]
+		switch state {
+		case StateRepeat:
+			if c == '*' {
+				curr.Repeat = true
+				i++
+			}
+			state = StateLength
+		case StateLength:
+			if isDigit(c) {
+				curr.Length = 10*curr.Length + int(c-'0')
+				if curr.Length > 0xffff {
+					return nil, fmt.Errorf("Octet length too large at offset %d in DISPLAY-HINT %q", i, hint)
+				}
+				hasLength = true
+				i++
+				break
+			}
+			if !hasLength {
+				return nil, fmt.Errorf("Missing octet length at offset %d in DISPLAY-HINT %q", i, hint)
+			}
+			state = StateFormat
+		case StateFormat:
+			switch c {
+			case 'a', 't':
+			case 'd', 'o', 'x':
+				curr.Numeric = true
+			default:
+				return nil, fmt.Errorf("Invalid format %q at offset %d in DISPLAY-HINT %q", c, i, hint)
+			}
+			curr.Kind = c
+			i++
+			state = StateSeparator
+		case StateSeparator:
+			if c == '*' || isDigit(c) {
+				state = StateEnd
+				break
+			}
+			curr.Separator = c
+			i++
+			if curr.Repeat {
+				state = StateTerminator
+			} else {
+				state = StateEnd
+			}
+		case StateTerminator:
+			if c == '*' || isDigit(c) {
+				state = StateEnd
+				break
+			}
+			curr.Terminator = c
+			i++
+			state = StateEnd
+		case StateEnd:
+			h.specs = append(h.specs, curr)
+			curr = StringHint{}
+			hasLength = false
+			state = StateRepeat
+		}
+	}
+	if state < StateSeparator {
+		return nil, fmt.Errorf("Incomplete octet format at end of DISPLAY-HINT %q", hint)
+	}
+	h.specs = append(h.specs, curr)
+	// The last octet-format is applied until the value is exhausted, so it must consume something
+	if !curr.Repeat && curr.Length == 0 {
+		return nil, fmt.Errorf("Last octet format of DISPLAY-HINT %q does not consume any octets", hint)
+	}
+	return h, nil
+}
+
+var displayHints sync.Map
+
+// getDisplayHint returns the compiled DISPLAY-HINT, caching the result. An
+// invalid hint is treated as if there were no hint.
+func getDisplayHint(hint string) *DisplayHint {
+	if h, ok := displayHints.Load(hint); ok {
+		return h.(*DisplayHint)
+	}
+	h, err := CompileDisplayHint(hint)
+	if err != nil {
+		h = &DisplayHint{hint: hint}
+	}
+	displayHints.Store(hint, h)
+	return h
+}
+
+func (h *DisplayHint) String() string {
+	return h.hint
+}
+
+// Specs returns the octet-formats of the hint, which is empty for an invalid
+// or missing hint
+func (h *DisplayHint) Specs() []StringHint {
+	return append([]StringHint(nil), h.specs...)
+}
+
+// utf8Boundary returns the length of the octets without any trailing
+// incomplete UTF-8 sequence, as long as something is left
+func utf8Boundary(octets []byte) int {
+	for i := len(octets) - 1; i > 0 && i >= len(octets)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(octets[i]) {
+			if !utf8.FullRune(octets[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(octets)
+}
+
+// end returns the end of the octets that the octet-format is applied to,
+// starting at pos
+func (f StringHint) end(value []byte, pos int) int {
+	end := pos + f.Length
+	if end > len(value) {
+		end = len(value)
+	}
+	if f.Kind == 't' && end < len(value) {
+		end = pos + utf8Boundary(value[pos:end])
+	}
+	return end
+}
+
+func (f StringHint) format(b *strings.Builder, octets []byte) {
+	if len(octets) == 0 {
+		return
+	}
+	switch f.Kind {
+	case 'a':
+		for _, c := range octets {
+			b.WriteRune(rune(c))
+		}
+	case 't':
+		b.WriteString(strings.ToValidUTF8(string(octets), string(utf8.RuneError)))
+	case 'x':
+		b.WriteString(hex.EncodeToString(octets))
+	case 'd', 'o':
+		base := 10
+		if f.Kind == 'o' {
+			base = 8
+		}
+		if len(octets) <= 8 {
+			var n uint64
+			for _, c := range octets {
+				n = n<<8 | uint64(c)
+			}
+			b.WriteString(strconv.FormatUint(n, base))
+		} else {
+			b.WriteString(new(big.Int).SetBytes(octets).Text(base))
+		}
+	}
+}
+
+// walk applies the octet-formats to the value, calling item with the octets
+// of each application and delimiter with each separator or terminator
+func (h *DisplayHint) walk(value []byte, item func(f StringHint, start int, end int), delimiter func(c byte)) {
+	last := len(h.specs) - 1
+	for i, pos := 0, 0; pos < len(value); {
+		f := h.specs[i]
+		if i < last {
+			i++
+		}
+		count := 1
+		if f.Repeat {
+			count = int(value[pos])
+			pos++
+			if count == 0 && f.Terminator > 0 && pos < len(value) {
+				delimiter(f.Terminator)
+			}
+		}
+		for n := 1; n <= count && pos < len(value); n++ {
+			end := f.end(value, pos)
+			item(f, pos, end)
+			pos = end
+			if pos >= len(value) {
+				break
+			}
+			if n == count && f.Terminator > 0 {
+				delimiter(f.Terminator)
+			} else if f.Separator > 0 {
+				delimiter(f.Separator)
+			}
+		}
+	}
+}
+
+func (h *DisplayHint) Format(value []byte) string {
+	if len(h.specs) == 0 {
+		return fmt.Sprintf("% X", value)
+	}
+	var b strings.Builder
+	h.walk(value, func(f StringHint, start int, end int) {
+		f.format(&b, value[start:end])
+	}, func(c byte) {
+		b.WriteByte(c)
+	})
+	return b.String()
+}
+
+// Validate checks that the octets displayed by 'a' octet-formats are NVT
+// ASCII, with CR only followed by LF or NUL, and that the octets displayed by
+// 't' octet-formats are UTF-8
+func (h *DisplayHint) Validate(value []byte) (err error) {
+	h.walk(value, func(f StringHint, start int, end int) {
+		if err != nil {
+			return
+		}
+		switch f.Kind {
+		case 'a':
+			for i := start; i < end; i++ {
+				if value[i] > 0x7f {
+					err = fmt.Errorf("%w: octet 0x%02x at offset %d is not NVT ASCII", ErrInvalidCharset, value[i], i)
+					return
+				}
+				if value[i] == '\r' && (i+1 == len(value) || (value[i+1] != '\n' && value[i+1] != 0)) {
+					err = fmt.Errorf("%w: CR at offset %d is not followed by LF or NUL", ErrInvalidCharset, i)
+					return
+				}
+			}
+		case 't':
+			for i := start; i < end; {
+				r, size := utf8.DecodeRune(value[i:end])
+				if r == utf8.RuneError && size <= 1 {
+					err = fmt.Errorf("%w: invalid UTF-8 at offset %d", ErrInvalidCharset, i)
+					return
+				}
+				i += size
+			}
+		}
+	}, func(byte) {})
+	return
+}
+
+func (f StringHint) radix() (radix int, name string) {
+	switch f.Kind {
+	case 'o':
+		return 8, "octal"
+	case 'x':
+		return 16, "hexadecimal"
+	}
+	return 10, "decimal"
+}
+
+func (f StringHint) isDelimiter(c byte) bool {
+	return (f.Separator > 0 && c == f.Separator) || (f.Terminator > 0 && c == f.Terminator)
+}
+
+func (f StringHint) parse(s string, pos int, out []byte) (int, []byte, error) {
+	if f.Length == 0 {
+		return pos, out, nil
+	}
+	end := pos
+	switch f.Kind {
+	case 'a':
+		// Formatted octets above 0x7f are decoded as the equivalent rune
+		for n := 0; n < f.Length && end < len(s) && !f.isDelimiter(s[end]); n++ {
+			r, size := utf8.DecodeRuneInString(s[end:])
+			if r > 0xff {
+				return pos, out, parseErrorf(s, end, "Character %q is not ASCII", r)
+			}
+			out = append(out, byte(r))
+			end += size
+		}
+	case 't':
+		for end < len(s) && !f.isDelimiter(s[end]) {
+			_, size := utf8.DecodeRuneInString(s[end:])
+			if end-pos+size > f.Length {
+				break
+			}
+			end += size
+		}
+		out = append(out, s[pos:end]...)
+	default:
+		radix, name := f.radix()
+		max := new(big.Int).Lsh(big.NewInt(1), uint(8*f.Length))
+		maxDigits := len(max.Sub(max, big.NewInt(1)).Text(radix))
+		for end < len(s) && end-pos < maxDigits && isRadixDigit(s[end], radix) {
+			end++
+		}
+		if end == pos {
+			return pos, out, parseErrorf(s, pos, "Expected %s number", name)
+		}
+		n, _ := new(big.Int).SetString(s[pos:end], radix)
+		if n.Cmp(max) > 0 {
+			return pos, out, parseErrorf(s, pos, "Number %s does not fit in %d octets", s[pos:end], f.Length)
+		}
+		return end, append(out, n.FillBytes(make([]byte, f.Length))...), nil
+	}
+	if end == pos {
+		return pos, out, parseErrorf(s, pos, "Expected text")
+	}
+	return end, out, nil
+}
+
+// Parse is the inverse of Format. Without any octet-formats, the input is
+// expected to be hexadecimal octets, as formatted, or a double-quoted string.
+func (h *DisplayHint) Parse(s string) ([]byte, error) {
+	if len(h.specs) == 0 {
+		if len(s) > 0 && s[0] == '"' {
+			str, err := strconv.Unquote(s)
+			if err != nil {
+				return nil, parseErrorf(s, 0, "Invalid quoted string: %w", err)
+			}
+			return []byte(str), nil
+		}
+		return parseHexOctets(s)
+	}
+	last := len(h.specs) - 1
+	out := make([]byte, 0, len(s))
+	var err error
+	for i, pos := 0, 0; pos < len(s); {
+		f := h.specs[i]
+		if i < last {
+			i++
+		}
+		if !f.Repeat {
+			if pos, out, err = f.parse(s, pos, out); err != nil {
+				return nil, err
+			}
+			if f.Separator > 0 && pos < len(s) {
+				if s[pos] != f.Separator {
+					return nil, parseErrorf(s, pos, "Expected separator %q", f.Separator)
+				}
+				pos++
+			}
+			continue
+		}
+		countPos := len(out)
+		out = append(out, 0)
+		count := 0
+		for pos < len(s) {
+			if f.Terminator > 0 && s[pos] == f.Terminator {
+				pos++
+				break
+			}
+			if count == 0xff {
+				return nil, parseErrorf(s, pos, "Too many repetitions, at most 255 allowed")
+			}
+			if pos, out, err = f.parse(s, pos, out); err != nil {
+				return nil, err
+			}
+			count++
+			if pos == len(s) || (f.Terminator > 0 && s[pos] == f.Terminator) {
+				continue
+			}
+			if f.Separator > 0 {
+				if s[pos] != f.Separator {
+					if f.Terminator > 0 {
+						return nil, parseErrorf(s, pos, "Expected separator %q or terminator %q", f.Separator, f.Terminator)
+					}
+					break
+				}
+				pos++
+			}
+		}
+		out[countPos] = byte(count)
+	}
+	return out, nil
+}
+
+// IntegerHint is a compiled INTEGER DISPLAY-HINT, as defined in RFC 2579
+// section 3.1
+type IntegerHint struct {
+	Kind     byte // One of d, x, o or b
+	Decimals int  // Implied decimal places for d-N
+}
+
+func CompileIntegerHint(hint string) (h IntegerHint, err error) {
+	h.Kind = 'd'
+	if hint == "" {
+		return
+	}
+	switch hint[0] {
+	case 'd':
+		if len(hint) == 1 {
+			break
+		}
+		if hint[1] != '-' || len(hint) == 2 {
+			return IntegerHint{Kind: 'd'}, fmt.Errorf("Invalid DISPLAY-HINT %q", hint)
+		}
+		for i := 2; i < len(hint); i++ {
+			if !isDigit(hint[i]) {
+				return IntegerHint{Kind: 'd'}, fmt.Errorf("Invalid decimal places at offset %d in DISPLAY-HINT %q", i, hint)
+			}
+		}
+		if h.Decimals, err = strconv.Atoi(hint[2:]); err != nil || h.Decimals > 20 {
+			return IntegerHint{Kind: 'd'}, fmt.Errorf("Too many decimal places in DISPLAY-HINT %q", hint)
+		}
+	case 'x', 'o', 'b':
+		if len(hint) > 1 {
+			return IntegerHint{Kind: 'd'}, fmt.Errorf("Invalid DISPLAY-HINT %q", hint)
+		}
+		h.Kind = hint[0]
+	default:
+		return IntegerHint{Kind: 'd'}, fmt.Errorf("Invalid format %q in DISPLAY-HINT %q", hint[0], hint)
+	}
+	return
+}
+
+func (h IntegerHint) radix() int {
+	switch h.Kind {
+	case 'b':
+		return 2
+	case 'o':
+		return 8
+	case 'x':
+		return 16
+	}
+	return 10
+}
+
+func (h IntegerHint) Format(value int64) string {
+	return h.insertDecimalPoint(strconv.FormatInt(value, h.radix()))
+}
+
+func (h IntegerHint) FormatUint(value uint64) string {
+	return h.insertDecimalPoint(strconv.FormatUint(value, h.radix()))
+}
+
+func (h IntegerHint) insertDecimalPoint(formatted string) string {
+	if h.Decimals == 0 {
+		return formatted
+	}
+	offset := 0
+	if formatted[0] == '-' {
+		offset = 1
+	}
+	if len(formatted)-offset <= h.Decimals {
+		zeros := h.Decimals - len(formatted) + offset
+		return formatted[:offset] + "0." + strings.Repeat("0", zeros) + formatted[offset:]
+	}
+	return formatted[:len(formatted)-h.Decimals] + "." + formatted[len(formatted)-h.Decimals:]
+}
+
+// digits returns the digits of a formatted value with any 0x prefix and
+// decimal point removed, along with the offset of the number in s
+func (h IntegerHint) digits(s string) (string, int, error) {
+	trimmed := strings.TrimSpace(s)
+	offset := strings.Index(s, trimmed)
+	if trimmed == "" {
+		return "", 0, parseErrorf(s, 0, "Expected integer")
+	}
+	str, sign := trimmed, ""
+	switch str[0] {
+	case '-':
+		sign, str = "-", str[1:]
+	case '+':
+		str = str[1:]
+	}
+	start := offset + len(trimmed) - len(str)
+	if h.Decimals == 0 {
+		if h.Kind == 'x' && (strings.HasPrefix(str, "0x") || strings.HasPrefix(str, "0X")) {
+			str = str[2:]
+		}
+		return sign + str, offset, nil
+	}
+
+	intPart, fracPart := str, ""
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		intPart, fracPart = str[:i], str[i+1:]
+	}
+	fracOffset := start + len(intPart) + 1
+	if len(fracPart) > h.Decimals {
+		if strings.Trim(fracPart[h.Decimals:], "0") != "" {
+			return "", 0, parseErrorf(s, fracOffset+h.Decimals, "Too many decimal places, at most %d allowed", h.Decimals)
+		}
+		fracPart = fracPart[:h.Decimals]
+	}
+	for i := 0; i < len(fracPart); i++ {
+		if !isDigit(fracPart[i]) {
+			return "", 0, parseErrorf(s, fracOffset+i, "Invalid decimal digit %q", fracPart[i])
+		}
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	return sign + intPart + fracPart + strings.Repeat("0", h.Decimals-len(fracPart)), offset, nil
+}
+
+// Parse is the inverse of Format. Hexadecimal values may have a 0x prefix
+// and excess decimal places are accepted as long as they are zero.
+func (h IntegerHint) Parse(s string) (int64, error) {
+	digits, offset, err := h.digits(s)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseInt(digits, h.radix(), 64)
+	if err != nil {
+		return 0, parseErrorf(s, offset, "Invalid integer: %w", errors.Unwrap(err))
+	}
+	return value, nil
+}
+
+// ParseUint is the inverse of FormatUint
+func (h IntegerHint) ParseUint(s string) (uint64, error) {
+	digits, offset, err := h.digits(s)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseUint(digits, h.radix(), 64)
+	if err != nil {
+		return 0, parseErrorf(s, offset, "Invalid unsigned integer: %w", errors.Unwrap(err))
+	}
+	return value, nil
+}
+
+var integerHints sync.Map
+
+// getIntegerHint returns the compiled integer DISPLAY-HINT, caching the
+// result. An invalid hint is treated as "d".
+func getIntegerHint(hint string) IntegerHint {
+	if h, ok := integerHints.Load(hint); ok {
+		return h.(IntegerHint)
+	}
+	h, _ := CompileIntegerHint(hint)
+	integerHints.Store(hint, h)
+	return h
+}
+
+// IntegerDisplayHint formats the value according to the DISPLAY-HINT, which
+// is treated as "d" if invalid
+func IntegerDisplayHint(format string, value int64) string {
+	return getIntegerHint(format).Format(value)
+}
+
+// UnsignedDisplayHint formats an unsigned 64-bit value according to the
+// DISPLAY-HINT, which is treated as "d" if invalid
+func UnsignedDisplayHint(format string, value uint64) string {
+	return getIntegerHint(format).FormatUint(value)
+}
+
+// ParseIntegerDisplayHint is the inverse of IntegerDisplayHint
+func ParseIntegerDisplayHint(format string, s string) (int64, error) {
+	return getIntegerHint(format).Parse(s)
+}
+
+// ParseUnsignedDisplayHint is the inverse of UnsignedDisplayHint
+func ParseUnsignedDisplayHint(format string, s string) (uint64, error) {
+	return getIntegerHint(format).ParseUint(s)
+}
+
+// StringDisplayHint formats the value according to the DISPLAY-HINT, which is
+// treated as if missing if invalid
+func StringDisplayHint(format string, value []byte) string {
+	return getDisplayHint(format).Format(value)
+}
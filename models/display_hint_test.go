@@ -0,0 +1,172 @@
+package models_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi/models"
+)
+
+var stringDisplayHintTests = []struct {
+	source    string
+	hint      string
+	value     []byte
+	formatted string
+	lossy     bool // Parsing the formatted value does not give back the value
+}{
+	{"SNMPv2-TC::DisplayString", "255a", []byte("SNMP agent"), "SNMP agent", false},
+	{"SNMPv2-TC::PhysAddress", "1x:", []byte{0x00, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e}, "00:1a:2b:3c:4d:5e", false},
+	{"SNMPv2-TC::MacAddress", "1x:", []byte{0x00, 0x00, 0x5e, 0x00, 0x53, 0x01}, "00:00:5e:00:53:01", false},
+	{"SNMPv2-TC::DateAndTime", "2d-1d-1d,1d:1d:1d.1d,1a1d:1d", []byte{0x07, 0xe8, 0x05, 0x1a, 0x0d, 0x1e, 0x0f, 0x00, '-', 0x04, 0x00}, "2024-5-26,13:30:15.0,-4:0", false},
+	{"SNMPv2-TC::DateAndTime without time zone", "2d-1d-1d,1d:1d:1d.1d,1a1d:1d", []byte{0x07, 0xcf, 0x0c, 0x1f, 0x17, 0x3b, 0x3b, 0x09}, "1999-12-31,23:59:59.9", false},
+	{"SNMP-FRAMEWORK-MIB::SnmpAdminString", "255t", []byte("Grüße"), "Grüße", false},
+	{"INET-ADDRESS-MIB::InetAddressIPv4", "1d.1d.1d.1d", []byte{192, 0, 2, 1}, "192.0.2.1", false},
+	{"INET-ADDRESS-MIB::InetAddressIPv6", "2x:2x:2x:2x:2x:2x:2x:2x", []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}, "2001:0db8:0000:0000:0000:0000:0000:0001", false},
+	{"INET-ADDRESS-MIB::InetAddressIPv4z", "1d.1d.1d.1d%4d", []byte{192, 0, 2, 1, 0, 0, 0, 3}, "192.0.2.1%3", false},
+	{"INET-ADDRESS-MIB::InetAddressIPv6z", "2x:2x:2x:2x:2x:2x:2x:2x%4d", []byte{0xfe, 0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 4}, "fe80:0000:0000:0000:0000:0000:0000:0001%4", false},
+	{"TRANSPORT-ADDRESS-MIB::TransportAddressIPv4", "1d.1d.1d.1d:2d", []byte{192, 0, 2, 1, 0x00, 0xa1}, "192.0.2.1:161", false},
+	{"TRANSPORT-ADDRESS-MIB::TransportAddressIPv6", "0a[2x:2x:2x:2x:2x:2x:2x:2x]0a:2d", []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0x00, 0xa2}, "[2001:0db8:0000:0000:0000:0000:0000:0001]:162", false},
+	{"TRANSPORT-ADDRESS-MIB::TransportAddressDns", "1a", []byte("localhost"), "localhost", false},
+	{"Repeat with separator and terminator", "*1d./", []byte{2, 1, 2, 2, 3, 4}, "1.2/3.4", false},
+	{"Repeat count of zero", "*1d./1a", []byte{0, 'a', 'b'}, "/ab", false},
+	{"Multi-octet decimal group", "4d", []byte{0x00, 0x01, 0x00, 0x00}, "65536", false},
+	{"Multi-octet octal group", "2o", []byte{0x01, 0xff}, "777", false},
+	{"Multi-octet hex group", "3x ", []byte{0x00, 0x00, 0x01, 0xab, 0xcd, 0xef}, "000001 abcdef", false},
+	{"Numeric group longer than 8 octets", "9d", []byte{1, 0, 0, 0, 0, 0, 0, 0, 0}, "18446744073709551616", false},
+	{"Short final numeric group", "2d.", []byte{0x00, 0x01, 0x02}, "1.2", true},
+	{"UTF-8 is not split across groups", "2t/", []byte("aé"), "a/é", false},
+	{"Missing hint", "", []byte{0xde, 0xad}, "DE AD", false},
+	{"Invalid format", "1q", []byte{0xde, 0xad}, "DE AD", false},
+	{"Missing octet length", "x:", []byte{0xde, 0xad}, "DE AD", false},
+	{"Incomplete octet format", "1x:*1", []byte{0xde, 0xad}, "DE AD", false},
+	{"Last octet format consumes nothing", "1x:0a", []byte{0xde, 0xad}, "DE AD", false},
+}
+
+func TestStringDisplayHint(t *testing.T) {
+	for _, test := range stringDisplayHintTests {
+		formatted := models.StringDisplayHint(test.hint, test.value)
+		if formatted != test.formatted {
+			t.Errorf("%s: StringDisplayHint(%q, % x) = %q, want %q", test.source, test.hint, test.value, formatted, test.formatted)
+			continue
+		}
+		if test.lossy {
+			continue
+		}
+		value, err := models.ParseStringDisplayHint(test.hint, formatted)
+		if err != nil {
+			t.Errorf("%s: ParseStringDisplayHint(%q, %q) returned error: %v", test.source, test.hint, formatted, err)
+		} else if !bytes.Equal(value, test.value) {
+			t.Errorf("%s: ParseStringDisplayHint(%q, %q) = % x, want % x", test.source, test.hint, formatted, value, test.value)
+		}
+	}
+}
+
+func TestCompileDisplayHint(t *testing.T) {
+	h, err := models.CompileDisplayHint("*1d./1x:")
+	if err != nil {
+		t.Fatalf("CompileDisplayHint returned error: %v", err)
+	}
+	want := []models.StringHint{
+		{Repeat: true, Length: 1, Kind: 'd', Separator: '.', Terminator: '/', Numeric: true},
+		{Length: 1, Kind: 'x', Separator: ':', Numeric: true},
+	}
+	specs := h.Specs()
+	if len(specs) != len(want) {
+		t.Fatalf("Specs() = %+v, want %+v", specs, want)
+	}
+	for i := range want {
+		if specs[i] != want[i] {
+			t.Errorf("Specs()[%d] = %+v, want %+v", i, specs[i], want[i])
+		}
+	}
+	for _, test := range stringDisplayHintTests {
+		if test.formatted != "DE AD" || test.hint == "" {
+			continue
+		}
+		if _, err := models.CompileDisplayHint(test.hint); err == nil {
+			t.Errorf("%s: CompileDisplayHint(%q) did not return an error", test.source, test.hint)
+		}
+	}
+}
+
+func TestStringDisplayHintParseErrors(t *testing.T) {
+	tests := []struct {
+		hint   string
+		input  string
+		offset int
+	}{
+		{"1x:", "00:1g", 4},
+		{"1d.1d.1d.1d", "192.0.2.256", 8},
+		{"1d.1d.1d.1d", "192.0.2-1", 7},
+		{"*1d./", "1.2.x", 4},
+	}
+	for _, test := range tests {
+		_, err := models.ParseStringDisplayHint(test.hint, test.input)
+		parseErr, ok := err.(*models.ParseError)
+		if !ok {
+			t.Errorf("ParseStringDisplayHint(%q, %q) error = %v, want *ParseError", test.hint, test.input, err)
+		} else if parseErr.Offset != test.offset {
+			t.Errorf("ParseStringDisplayHint(%q, %q) error offset = %d, want %d", test.hint, test.input, parseErr.Offset, test.offset)
+		}
+	}
+}
+
+var integerDisplayHintTests = []struct {
+	hint      string
+	value     int64
+	formatted string
+}{
+	{"", 42, "42"},
+	{"d", -42, "-42"},
+	{"d-2", 1234, "12.34"},
+	{"d-2", -1234, "-12.34"},
+	{"d-2", 5, "0.05"},
+	{"d-2", -5, "-0.05"},
+	{"d-2", 0, "0.00"},
+	{"d-1", -10, "-1.0"},
+	{"d-3", -1000, "-1.000"},
+	{"x", 255, "ff"},
+	{"o", 8, "10"},
+	{"b", 5, "101"},
+	{"d-", 42, "42"},
+	{"q", 42, "42"},
+	{"x2", 42, "42"},
+}
+
+func TestIntegerDisplayHint(t *testing.T) {
+	for _, test := range integerDisplayHintTests {
+		formatted := models.IntegerDisplayHint(test.hint, test.value)
+		if formatted != test.formatted {
+			t.Errorf("IntegerDisplayHint(%q, %d) = %q, want %q", test.hint, test.value, formatted, test.formatted)
+			continue
+		}
+		value, err := models.ParseIntegerDisplayHint(test.hint, formatted)
+		if err != nil {
+			t.Errorf("ParseIntegerDisplayHint(%q, %q) returned error: %v", test.hint, formatted, err)
+		} else if value != test.value {
+			t.Errorf("ParseIntegerDisplayHint(%q, %q) = %d, want %d", test.hint, formatted, value, test.value)
+		}
+	}
+}
+
+func TestIntegerDisplayHintParseErrors(t *testing.T) {
+	tests := []struct {
+		hint   string
+		input  string
+		offset int
+	}{
+		{"d-1", "1.5x  ", 3},
+		{"d-1", "1.x ", 2},
+		{"d-2", "  -1.2y", 6},
+		{"d-2", " +1.234 ", 6},
+	}
+	for _, test := range tests {
+		_, err := models.ParseIntegerDisplayHint(test.hint, test.input)
+		parseErr, ok := err.(*models.ParseError)
+		if !ok {
+			t.Errorf("ParseIntegerDisplayHint(%q, %q) error = %v, want *ParseError", test.hint, test.input, err)
+		} else if parseErr.Offset != test.offset {
+			t.Errorf("ParseIntegerDisplayHint(%q, %q) error offset = %d, want %d", test.hint, test.input, parseErr.Offset, test.offset)
+		}
+	}
+}
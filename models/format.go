@@ -4,6 +4,8 @@ package models
 
 import (
 	"fmt"
+	"math"
+	"net"
 	"strconv"
 	"time"
 
@@ -20,7 +22,8 @@ const (
 	FormatString
 	FormatUnits
 	FormatDurationShort
-	FormatAll Format = 0xff & ^FormatUnits
+	FormatUnitsScaled        // Like FormatUnits, but scaled to a human-readable form where the unit is known
+	FormatAll         Format = 0xff & ^(FormatUnits | FormatUnitsScaled)
 )
 
 func ResolveFormat(formats []Format, defaultFormat ...Format) (format Format) {
@@ -37,13 +40,21 @@ func ResolveFormat(formats []Format, defaultFormat ...Format) (format Format) {
 }
 
 type Value struct {
+	BaseType  types.BaseType
 	Format    Format
 	Formatted string
 	Raw       interface{}
+	Label     string   // Name of an enumerated value
+	Bits      []string // Names of the set bits of a BITS value
+	Units     string
+	Err       error // Set if the value could not be converted to the type
 }
 
 func (v Value) Bytes() []byte {
-	if b, ok := v.Raw.([]byte); ok {
+	switch b := v.Raw.(type) {
+	case []byte:
+		return b
+	case net.HardwareAddr:
 		return b
 	}
 	if s, ok := v.Raw.(string); ok {
@@ -53,21 +64,48 @@ func (v Value) Bytes() []byte {
 }
 
 func (v Value) Duration() time.Duration {
-	if d, ok := v.Raw.(time.Duration); ok {
+	switch d := v.Raw.(type) {
+	case time.Duration:
 		return d
+	case TimeStamp:
+		return d.Duration()
 	}
 	return time.Duration(0)
 }
 
+func (v Value) Time() time.Time {
+	if t, ok := v.Raw.(time.Time); ok {
+		return t
+	}
+	return time.Time{}
+}
+
+func (v Value) Bool() bool {
+	if b, ok := v.Raw.(bool); ok {
+		return b
+	}
+	return false
+}
+
+// Int64 returns the raw value as an int64. An unsigned value above
+// math.MaxInt64 wraps around.
 func (v Value) Int64() int64 {
-	if i, ok := v.Raw.(int64); ok {
+	switch i := v.Raw.(type) {
+	case int64:
 		return i
+	case uint64:
+		return int64(i)
 	}
 	return 0
 }
 
+// Uint64 returns the raw value as a uint64. A negative signed value wraps
+// around.
 func (v Value) Uint64() uint64 {
-	if i, ok := v.Raw.(int64); ok {
+	switch i := v.Raw.(type) {
+	case uint64:
+		return i
+	case int64:
 		return uint64(i)
 	}
 	return 0
@@ -94,6 +132,9 @@ func ToInt64(value interface{}) (val int64, err error) {
 	case int64:
 		val = value
 	case uint64:
+		if value > math.MaxInt64 {
+			return 0, fmt.Errorf("Value %d overflows int64", value)
+		}
 		val = int64(value)
 	case int:
 		val = int64(value)
@@ -104,6 +145,9 @@ func ToInt64(value interface{}) (val int64, err error) {
 	case int32:
 		val = int64(value)
 	case uint:
+		if uint64(value) > math.MaxInt64 {
+			return 0, fmt.Errorf("Value %d overflows int64", value)
+		}
 		val = int64(value)
 	case uint8:
 		val = int64(value)
@@ -121,6 +165,35 @@ func ToInt64(value interface{}) (val int64, err error) {
 	return
 }
 
+func ToUint64(value interface{}) (val uint64, err error) {
+	switch value := value.(type) {
+	case uint64:
+		val = value
+	case uint:
+		val = uint64(value)
+	case uint8:
+		val = uint64(value)
+	case uint16:
+		val = uint64(value)
+	case uint32:
+		val = uint64(value)
+	case types.SmiSubId:
+		val = uint64(value)
+	case string:
+		return strconv.ParseUint(value, 10, 64)
+	default:
+		var signed int64
+		if signed, err = ToInt64(value); err != nil {
+			return
+		}
+		if signed < 0 {
+			return 0, fmt.Errorf("Value %d is negative", signed)
+		}
+		val = uint64(signed)
+	}
+	return
+}
+
 type ValueFormatter func(interface{}) Value
 
 func (n Node) FormatValue(value interface{}, flags ...Format) Value {
@@ -148,37 +221,33 @@ func (n ColumnNode) GetValueFormatter(flags ...Format) ValueFormatter {
 }
 
 func (t Type) FormatValue(value interface{}, flags ...Format) Value {
+	return t.GetValueFormatter(flags...)(value)
+}
+
+// GetValueFormatter returns the formatter for the type. Well-known textual
+// conventions and application types, matched by module-qualified identity,
+// have semantic formatters that produce typed raw values, such as time.Time
+// for SNMPv2-TC::DateAndTime. With FormatUnits, the UNITS of the type are
+// appended to the formatted value.
+func (t Type) GetValueFormatter(flags ...Format) ValueFormatter {
 	formatFlags := ResolveFormat(flags)
-	switch t.BaseType {
-	case types.BaseTypeOctetString:
-		switch t.Name {
-		case "IpAddress", "InetAddress", "IpV4orV6Addr":
-			return GetInetAddressFormatted(value, formatFlags)
-		}
-		return GetOctetStringFormatted(value, formatFlags, t.Format)
-	case types.BaseTypeBits:
-		if t.Enum == nil {
-			return GetBitsFormatted(value, formatFlags)
+	f := t.getBaseTypeFormatter(formatFlags)
+	return func(value interface{}) Value {
+		v := f(value)
+		v.BaseType = t.BaseType
+		if t.Units != "" {
+			v = t.formatUnits(v, formatFlags)
 		}
-		return GetEnumBitsFormatted(value, formatFlags, t.Enum)
-	case types.BaseTypeEnum:
-		return GetEnumFormatted(value, formatFlags, t.Enum)
-	}
-	switch t.Name {
-	case "TimeTicks", "TimeInterval", "TimeStamp":
-		return GetDurationFormatted(value, formatFlags)
+		return v
 	}
-	return GetIntFormatted(value, formatFlags, t.Format)
 }
 
-func (t Type) GetValueFormatter(flags ...Format) ValueFormatter {
-	formatFlags := ResolveFormat(flags)
+func (t Type) getBaseTypeFormatter(formatFlags Format) ValueFormatter {
+	if f := t.getTCFormatter(formatFlags); f != nil {
+		return f
+	}
 	switch t.BaseType {
 	case types.BaseTypeOctetString:
-		switch t.Name {
-		case "IpAddress", "InetAddress", "IpV4orV6Addr":
-			return GetInetAddressFormatter(formatFlags)
-		}
 		return GetOctetStringFormatter(formatFlags, t.Format)
 	case types.BaseTypeBits:
 		if t.Enum == nil {
@@ -187,10 +256,10 @@ func (t Type) GetValueFormatter(flags ...Format) ValueFormatter {
 		return GetEnumBitsFormatter(formatFlags, t.Enum)
 	case types.BaseTypeEnum:
 		return GetEnumFormatter(formatFlags, t.Enum)
-	}
-	switch t.Name {
-	case "TimeTicks", "TimeInterval", "TimeStamp":
-		return GetDurationFormatter(formatFlags)
+	case types.BaseTypeObjectIdentifier:
+		return GetObjectIdentifierFormatter(formatFlags)
+	case types.BaseTypeUnsigned64:
+		return GetUintFormatter(formatFlags, t.Format)
 	}
 	return GetIntFormatter(formatFlags, t.Format)
 }
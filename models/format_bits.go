@@ -7,11 +7,15 @@ import (
 
 func GetBitsFormatted(value interface{}, flags Format) (v Value) {
 	v.Format = flags
-	v.Raw = value
+	bytes, ok := octetStringValue(value)
+	if !ok {
+		v.Raw = value
+		v.Err = invalidTypeError(value, "a BITS")
+		return
+	}
+	v.Raw = bytes
 	if flags&FormatBits != 0 {
-		if bytes, ok := value.([]byte); ok {
-			v.Formatted = fmt.Sprintf("% X", bytes)
-		}
+		v.Formatted = fmt.Sprintf("% X", bytes)
 	}
 	return
 }
@@ -24,34 +28,36 @@ func GetBitsFormatter(flags Format) (f ValueFormatter) {
 
 func GetEnumBitsFormatted(value interface{}, flags Format, enum *Enum) (v Value) {
 	v.Format = flags
-	v.Raw = value
+	octets, ok := octetStringValue(value)
+	if !ok {
+		v.Raw = value
+		v.Err = invalidTypeError(value, "a BITS")
+		return
+	}
+	v.Raw = octets
+	v.Bits, _ = Type{Enum: enum}.BitsToNames(octets)
 	if flags == FormatNone {
 		return
 	}
-	octets := value.([]byte)
 	if flags&FormatBits != 0 {
 		v.Formatted = fmt.Sprintf("% X", octets)
 	}
 	if (flags&FormatEnumName)+(flags&FormatEnumValue) == 0 {
 		return
 	}
-	bitsFormatted := make([]string, 0, 8*len(octets))
-	for i, octet := range octets {
-		for j := 7; j >= 0; j-- {
-			if octet&(1<<uint(j)) != 0 {
-				bit := uint64(8*i + (7 - j))
-				var bitFormatted string
-				if flags&FormatEnumName != 0 {
-					bitFormatted = enum.Name(int64(bit))
-					if flags&FormatEnumValue != 0 || bitFormatted == "unknown" {
-						bitFormatted += "(" + fmt.Sprintf("%d", bit) + ")"
-					}
-				} else if flags&FormatEnumValue != 0 {
-					bitFormatted = fmt.Sprintf("%d", bit)
-				}
-				bitsFormatted = append(bitsFormatted, bitFormatted)
+	positions := BitPositions(octets)
+	bitsFormatted := make([]string, 0, len(positions))
+	for _, bit := range positions {
+		var bitFormatted string
+		if flags&FormatEnumName != 0 {
+			bitFormatted = enum.Name(int64(bit))
+			if flags&FormatEnumValue != 0 || bitFormatted == "unknown" {
+				bitFormatted += "(" + fmt.Sprintf("%d", bit) + ")"
 			}
+		} else if flags&FormatEnumValue != 0 {
+			bitFormatted = fmt.Sprintf("%d", bit)
 		}
+		bitsFormatted = append(bitsFormatted, bitFormatted)
 	}
 	if v.Formatted == "" {
 		v.Formatted = strings.Join(bitsFormatted, " ")
@@ -1,18 +1,21 @@
 package models
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
 )
 
 func GetDurationFormatted(value interface{}, flags Format) (v Value) {
+	v.Format = flags
 	intVal, err := ToInt64(value)
 	if err != nil {
+		v.Raw = value
+		v.Err = fmt.Errorf("%w: %v", ErrInvalidType, err)
 		return
 	}
 	duration := time.Duration(intVal * 1e7)
-	v.Format = flags
 	v.Raw = duration
 	if flags == FormatNone {
 		return
@@ -7,10 +7,20 @@ import (
 func GetEnumFormatted(value interface{}, flags Format, enum *Enum) (v Value) {
 	intVal, err := ToInt64(value)
 	v.Format = flags
-	v.Raw = intVal
+	if name, ok := value.(string); ok && err != nil {
+		if intVal, err = enum.Value(name); err != nil {
+			v.Raw = value
+			v.Err = fmt.Errorf("%w: %q", ErrUnknownEnum, name)
+			return
+		}
+	}
 	if err != nil {
+		v.Raw = value
+		v.Err = fmt.Errorf("%w: %v", ErrInvalidType, err)
 		return
 	}
+	v.Raw = intVal
+	v.Label, _ = enum.lookup(intVal)
 	if flags&FormatEnumName != 0 {
 		v.Formatted = enum.Name(intVal)
 		if flags&FormatEnumValue != 0 {
@@ -1,15 +1,24 @@
 package models
 
-import (
-	"fmt"
-	"strconv"
-	"strings"
-)
+import "fmt"
 
+// GetIntFormatted formats a signed integer value. Unsigned 64-bit values are
+// kept as uint64, so that values above math.MaxInt64 are not lost.
 func GetIntFormatted(value interface{}, flags Format, format string) Value {
+	switch value.(type) {
+	case uint64, uint:
+		return GetUintFormatted(value, flags, format)
+	}
 	var formatted string
 	intVal, err := ToInt64(value)
-	if err == nil && flags != FormatNone {
+	if err != nil {
+		return Value{
+			Format: flags,
+			Raw:    value,
+			Err:    fmt.Errorf("%w: %v", ErrInvalidType, err),
+		}
+	}
+	if flags != FormatNone {
 		formatted = IntegerDisplayHint(format, intVal)
 	}
 	return Value{
@@ -25,39 +34,28 @@ func GetIntFormatter(flags Format, format string) (f ValueFormatter) {
 	}
 }
 
-func IntegerDisplayHint(format string, value int64) (formatted string) {
-	if len(format) == 0 {
-		return strconv.FormatInt(value, 10)
-	}
-	switch format[0] {
-	case 'b':
-		formatted = fmt.Sprintf("%b", value)
-	case 'd':
-		formatted = strconv.FormatInt(value, 10)
-		if len(format) < 3 {
-			break
-		}
-		decimals, err := strconv.Atoi(format[2:])
-		if err != nil || decimals < 1 {
-			break
-		}
-		formattedLen := len(formatted)
-		offset := 0
-		if formatted[0] == '-' {
-			offset = 1
-		}
-		if formattedLen-offset <= decimals {
-			zeros := decimals - formattedLen + offset
-			formatted = formatted[:offset] + "0." + strings.Repeat("0", zeros) + formatted[offset:]
-		} else {
-			formatted = formatted[:formattedLen-decimals] + "." + formatted[formattedLen-decimals:]
+func GetUintFormatted(value interface{}, flags Format, format string) Value {
+	var formatted string
+	uintVal, err := ToUint64(value)
+	if err != nil {
+		return Value{
+			Format: flags,
+			Raw:    value,
+			Err:    fmt.Errorf("%w: %v", ErrInvalidType, err),
 		}
-	case 'o':
-		formatted = strconv.FormatInt(value, 8)
-	case 'x':
-		formatted = strconv.FormatInt(value, 16)
-	default:
-		formatted = strconv.FormatInt(value, 10)
-	}
-	return
+	}
+	if flags != FormatNone {
+		formatted = UnsignedDisplayHint(format, uintVal)
+	}
+	return Value{
+		Format:    flags,
+		Formatted: formatted,
+		Raw:       uintVal,
+	}
+}
+
+func GetUintFormatter(flags Format, format string) (f ValueFormatter) {
+	return func(value interface{}) Value {
+		return GetUintFormatted(value, flags, format)
+	}
 }
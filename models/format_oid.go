@@ -0,0 +1,58 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// ToOid converts the common representations of an OBJECT IDENTIFIER value to
+// a types.Oid
+func ToOid(value interface{}) (oid types.Oid, err error) {
+	switch v := value.(type) {
+	case types.Oid:
+		oid = v
+	case []types.SmiSubId:
+		oid = types.Oid(v)
+	case []uint32:
+		oid = make(types.Oid, len(v))
+		for i, subId := range v {
+			oid[i] = types.SmiSubId(subId)
+		}
+	case []int:
+		oid = make(types.Oid, len(v))
+		for i, subId := range v {
+			if subId < 0 || uint64(subId) > uint64(^types.SmiSubId(0)) {
+				return nil, fmt.Errorf("Invalid sub-identifier %d", subId)
+			}
+			oid[i] = types.SmiSubId(subId)
+		}
+	case string:
+		oid, err = types.OidFromString(strings.TrimPrefix(v, "."))
+	default:
+		err = fmt.Errorf("Value has invalid type: %T", value)
+	}
+	return
+}
+
+func GetObjectIdentifierFormatted(value interface{}, flags Format) (v Value) {
+	v.Format = flags
+	oid, err := ToOid(value)
+	if err != nil {
+		v.Raw = value
+		v.Err = fmt.Errorf("%w: %v", ErrInvalidType, err)
+		return
+	}
+	v.Raw = oid
+	if flags != FormatNone {
+		v.Formatted = oid.String()
+	}
+	return
+}
+
+func GetObjectIdentifierFormatter(flags Format) (f ValueFormatter) {
+	return func(value interface{}) Value {
+		return GetObjectIdentifierFormatted(value, flags)
+	}
+}
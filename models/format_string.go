@@ -14,7 +14,9 @@ const (
 	_Format_name_3 = "Bits"
 	_Format_name_4 = "String"
 	_Format_name_5 = "Units"
-	_Format_name_6 = "All"
+	_Format_name_6 = "DurationShort"
+	_Format_name_7 = "All"
+	_Format_name_8 = "UnitsScaled"
 )
 
 var (
@@ -24,7 +26,9 @@ var (
 	_Format_index_3 = [...]uint8{0, 4}
 	_Format_index_4 = [...]uint8{0, 6}
 	_Format_index_5 = [...]uint8{0, 5}
-	_Format_index_6 = [...]uint8{0, 3}
+	_Format_index_6 = [...]uint8{0, 13}
+	_Format_index_7 = [...]uint8{0, 3}
+	_Format_index_8 = [...]uint8{0, 11}
 )
 
 func (i Format) String() string {
@@ -41,21 +45,27 @@ func (i Format) String() string {
 		return _Format_name_4
 	case i == 32:
 		return _Format_name_5
-	case i == 255:
+	case i == 64:
 		return _Format_name_6
+	case i == 95:
+		return _Format_name_7
+	case i == 128:
+		return _Format_name_8
 	default:
 		return fmt.Sprintf("Format(%d)", i)
 	}
 }
 
 var _FormatNameToValue_map = map[string]Format{
-	_Format_name_0[0:4]: 0,
-	_Format_name_1[0:8]: 2,
-	_Format_name_2[0:9]: 4,
-	_Format_name_3[0:4]: 8,
-	_Format_name_4[0:6]: 16,
-	_Format_name_5[0:5]: 32,
-	_Format_name_6[0:3]: 255,
+	_Format_name_0[0:4]:  0,
+	_Format_name_1[0:8]:  2,
+	_Format_name_2[0:9]:  4,
+	_Format_name_3[0:4]:  8,
+	_Format_name_4[0:6]:  16,
+	_Format_name_5[0:5]:  32,
+	_Format_name_6[0:13]: 64,
+	_Format_name_7[0:3]:  95,
+	_Format_name_8[0:11]: 128,
 }
 
 func FormatFromString(s string) (Format, error) {
@@ -0,0 +1,633 @@
+package models
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"time"
+)
+
+// Identity returns the module-qualified name of the type, such as
+// SNMPv2-TC::DateAndTime, or just the name if the module is not known
+func (t Type) Identity() string {
+	if t.Module == "" {
+		return t.Name
+	}
+	return t.Module + "::" + t.Name
+}
+
+type tcFormatter func(t Type, flags Format) ValueFormatter
+
+// tcFormatters are the semantic formatters for well-known textual conventions
+// and application types, keyed by module-qualified identity
+var tcFormatters = map[string]tcFormatter{
+	"RFC1065-SMI::IpAddress":   ipAddressFormatter,
+	"RFC1155-SMI::IpAddress":   ipAddressFormatter,
+	"SNMPv2-SMI::IpAddress":    ipAddressFormatter,
+	"RFC1065-SMI::TimeTicks":   durationFormatter,
+	"RFC1155-SMI::TimeTicks":   durationFormatter,
+	"SNMPv2-SMI::TimeTicks":    durationFormatter,
+	"RFC1155-SMI::Opaque":      opaqueFormatter,
+	"SNMPv2-SMI::Opaque":       opaqueFormatter,
+	"RFC1213-MIB::PhysAddress": physAddressFormatter,
+	"SNMPv2-TC::PhysAddress":   physAddressFormatter,
+	"SNMPv2-TC::MacAddress":    physAddressFormatter,
+	"SNMPv2-TC::TruthValue":    truthValueFormatter,
+	"SNMPv2-TC::RowStatus":     rowStatusFormatter,
+	"SNMPv2-TC::StorageType":   storageTypeFormatter,
+	"SNMPv2-TC::TimeStamp":     timeStampFormatter,
+	"SNMPv2-TC::TimeInterval":  durationFormatter,
+	"SNMPv2-TC::DateAndTime":   dateAndTimeFormatter,
+
+	"INET-ADDRESS-MIB::InetAddressType":         inetAddressTypeFormatter,
+	"INET-ADDRESS-MIB::InetAddress":             inetAddressFormatter(InetAddressTypeUnknown),
+	"INET-ADDRESS-MIB::InetAddressIPv4":         inetAddressFormatter(InetAddressTypeIPv4),
+	"INET-ADDRESS-MIB::InetAddressIPv6":         inetAddressFormatter(InetAddressTypeIPv6),
+	"INET-ADDRESS-MIB::InetAddressIPv4z":        inetAddressFormatter(InetAddressTypeIPv4z),
+	"INET-ADDRESS-MIB::InetAddressIPv6z":        inetAddressFormatter(InetAddressTypeIPv6z),
+	"INET-ADDRESS-MIB::InetAddressDNS":          inetAddressFormatter(InetAddressTypeDNS),
+	"INET-ADDRESS-MIB::InetAddressPrefixLength": prefixLengthFormatter,
+}
+
+// legacyFormatters are used by bare name for types without a module
+var legacyFormatters = map[string]tcFormatter{
+	"IpAddress":    ipAddressFormatter,
+	"InetAddress":  ipAddressFormatter,
+	"IpV4orV6Addr": ipAddressFormatter,
+	"TimeTicks":    durationFormatter,
+	"TimeInterval": durationFormatter,
+	"TimeStamp":    durationFormatter,
+}
+
+func ipAddressFormatter(t Type, flags Format) ValueFormatter {
+	return GetInetAddressFormatter(flags)
+}
+
+func durationFormatter(t Type, flags Format) ValueFormatter {
+	return GetDurationFormatter(flags)
+}
+
+func octetStringValue(value interface{}) ([]byte, bool) {
+	if bytes, ok := getBytes(value); ok {
+		return bytes, true
+	}
+	if str, ok := value.(string); ok {
+		return []byte(str), true
+	}
+	return nil, false
+}
+
+func physAddressFormatter(t Type, flags Format) ValueFormatter {
+	format := t.Format
+	if format == "" {
+		format = "1x:"
+	}
+	return func(value interface{}) Value {
+		v := GetOctetStringFormatted(value, flags, format)
+		if bytes, ok := octetStringValue(value); ok {
+			v.Raw = net.HardwareAddr(bytes)
+		}
+		return v
+	}
+}
+
+func truthValueFormatter(t Type, flags Format) ValueFormatter {
+	return func(value interface{}) Value {
+		if b, ok := value.(bool); ok {
+			value = int64(TruthValueFalse)
+			if b {
+				value = int64(TruthValueTrue)
+			}
+		}
+		v := GetEnumFormatted(value, flags, t.Enum)
+		switch v.Raw {
+		case int64(TruthValueTrue):
+			v.Raw = true
+		case int64(TruthValueFalse):
+			v.Raw = false
+		}
+		return v
+	}
+}
+
+func rowStatusFormatter(t Type, flags Format) ValueFormatter {
+	return func(value interface{}) Value {
+		v := GetEnumFormatted(value, flags, t.Enum)
+		if v.Err == nil {
+			v.Raw = RowStatus(v.Int64())
+		}
+		return v
+	}
+}
+
+func storageTypeFormatter(t Type, flags Format) ValueFormatter {
+	return func(value interface{}) Value {
+		v := GetEnumFormatted(value, flags, t.Enum)
+		if v.Err == nil {
+			v.Raw = StorageType(v.Int64())
+		}
+		return v
+	}
+}
+
+func inetAddressTypeFormatter(t Type, flags Format) ValueFormatter {
+	return func(value interface{}) Value {
+		v := GetEnumFormatted(value, flags, t.Enum)
+		if v.Err == nil {
+			v.Raw = InetAddressType(v.Int64())
+		}
+		return v
+	}
+}
+
+func timeStampFormatter(t Type, flags Format) ValueFormatter {
+	return func(value interface{}) Value {
+		return GetTimeStampFormatted(value, flags)
+	}
+}
+
+func dateAndTimeFormatter(t Type, flags Format) ValueFormatter {
+	return func(value interface{}) Value {
+		return GetDateAndTimeFormatted(value, flags, t.Format)
+	}
+}
+
+func inetAddressFormatter(addrType InetAddressType) tcFormatter {
+	return func(t Type, flags Format) ValueFormatter {
+		return func(value interface{}) Value {
+			return GetInetAddressTypedFormatted(value, addrType, flags)
+		}
+	}
+}
+
+func prefixLengthFormatter(t Type, flags Format) ValueFormatter {
+	return func(value interface{}) Value {
+		v := GetIntFormatted(value, flags, t.Format)
+		if i, ok := v.Raw.(int64); ok && i >= 0 && i <= math.MaxUint32 {
+			v.Raw = uint32(i)
+		}
+		return v
+	}
+}
+
+func opaqueFormatter(t Type, flags Format) ValueFormatter {
+	return func(value interface{}) Value {
+		return GetOpaqueFormatted(value, flags)
+	}
+}
+
+type TruthValue int64
+
+const (
+	TruthValueTrue  TruthValue = 1
+	TruthValueFalse TruthValue = 2
+)
+
+type RowStatus int64
+
+const (
+	RowStatusActive        RowStatus = 1
+	RowStatusNotInService  RowStatus = 2
+	RowStatusNotReady      RowStatus = 3
+	RowStatusCreateAndGo   RowStatus = 4
+	RowStatusCreateAndWait RowStatus = 5
+	RowStatusDestroy       RowStatus = 6
+)
+
+var rowStatusNames = map[RowStatus]string{
+	RowStatusActive:        "active",
+	RowStatusNotInService:  "notInService",
+	RowStatusNotReady:      "notReady",
+	RowStatusCreateAndGo:   "createAndGo",
+	RowStatusCreateAndWait: "createAndWait",
+	RowStatusDestroy:       "destroy",
+}
+
+func (s RowStatus) String() string {
+	if name, ok := rowStatusNames[s]; ok {
+		return name
+	}
+	return "unknown(" + strconv.FormatInt(int64(s), 10) + ")"
+}
+
+type StorageType int64
+
+const (
+	StorageTypeOther       StorageType = 1
+	StorageTypeVolatile    StorageType = 2
+	StorageTypeNonVolatile StorageType = 3
+	StorageTypePermanent   StorageType = 4
+	StorageTypeReadOnly    StorageType = 5
+)
+
+var storageTypeNames = map[StorageType]string{
+	StorageTypeOther:       "other",
+	StorageTypeVolatile:    "volatile",
+	StorageTypeNonVolatile: "nonVolatile",
+	StorageTypePermanent:   "permanent",
+	StorageTypeReadOnly:    "readOnly",
+}
+
+func (s StorageType) String() string {
+	if name, ok := storageTypeNames[s]; ok {
+		return name
+	}
+	return "unknown(" + strconv.FormatInt(int64(s), 10) + ")"
+}
+
+type InetAddressType int64
+
+const (
+	InetAddressTypeUnknown InetAddressType = 0
+	InetAddressTypeIPv4    InetAddressType = 1
+	InetAddressTypeIPv6    InetAddressType = 2
+	InetAddressTypeIPv4z   InetAddressType = 3
+	InetAddressTypeIPv6z   InetAddressType = 4
+	InetAddressTypeDNS     InetAddressType = 16
+)
+
+var inetAddressTypeNames = map[InetAddressType]string{
+	InetAddressTypeUnknown: "unknown",
+	InetAddressTypeIPv4:    "ipv4",
+	InetAddressTypeIPv6:    "ipv6",
+	InetAddressTypeIPv4z:   "ipv4z",
+	InetAddressTypeIPv6z:   "ipv6z",
+	InetAddressTypeDNS:     "dns",
+}
+
+func (t InetAddressType) String() string {
+	if name, ok := inetAddressTypeNames[t]; ok {
+		return name
+	}
+	return "unknown(" + strconv.FormatInt(int64(t), 10) + ")"
+}
+
+// InetAddress is an INET-ADDRESS-MIB InetAddress decoded according to its
+// InetAddressType
+type InetAddress struct {
+	Type InetAddressType
+	IP   net.IP
+	Zone uint32 // Zone index for ipv4z and ipv6z
+	DNS  string // Name for dns
+	Raw  []byte // Octets of an address with an unknown type
+}
+
+// NewInetAddress decodes the octets of an InetAddress. For
+// InetAddressTypeUnknown, the type is inferred from the length of the octets.
+func NewInetAddress(addrType InetAddressType, octets []byte) (addr InetAddress, err error) {
+	if addrType == InetAddressTypeUnknown {
+		switch len(octets) {
+		case 4:
+			addrType = InetAddressTypeIPv4
+		case 8:
+			addrType = InetAddressTypeIPv4z
+		case 16:
+			addrType = InetAddressTypeIPv6
+		case 20:
+			addrType = InetAddressTypeIPv6z
+		}
+	}
+	addr.Type = addrType
+	switch addrType {
+	case InetAddressTypeIPv4, InetAddressTypeIPv6, InetAddressTypeIPv4z, InetAddressTypeIPv6z:
+		length := net.IPv4len
+		if addrType == InetAddressTypeIPv6 || addrType == InetAddressTypeIPv6z {
+			length = net.IPv6len
+		}
+		zoned := addrType == InetAddressTypeIPv4z || addrType == InetAddressTypeIPv6z
+		if zoned {
+			length += 4
+		}
+		if len(octets) != length {
+			return InetAddress{Type: addrType, Raw: octets}, fmt.Errorf("Invalid length %d for %s address", len(octets), addrType)
+		}
+		if zoned {
+			length -= 4
+			addr.Zone = binary.BigEndian.Uint32(octets[length:])
+		}
+		addr.IP = net.IP(append([]byte(nil), octets[:length]...))
+	case InetAddressTypeDNS:
+		addr.DNS = string(octets)
+	default:
+		addr.Raw = octets
+	}
+	return
+}
+
+func (a InetAddress) String() string {
+	switch a.Type {
+	case InetAddressTypeIPv4, InetAddressTypeIPv6:
+		if a.IP != nil {
+			return a.IP.String()
+		}
+	case InetAddressTypeIPv4z, InetAddressTypeIPv6z:
+		if a.IP != nil {
+			return a.IP.String() + "%" + strconv.FormatUint(uint64(a.Zone), 10)
+		}
+	case InetAddressTypeDNS:
+		return a.DNS
+	}
+	return fmt.Sprintf("% X", a.Raw)
+}
+
+// Octets returns the octets that the InetAddress was decoded from
+func (a InetAddress) Octets() []byte {
+	switch a.Type {
+	case InetAddressTypeIPv4, InetAddressTypeIPv6, InetAddressTypeIPv4z, InetAddressTypeIPv6z:
+		if a.IP == nil {
+			break
+		}
+		octets := append([]byte(nil), a.IP...)
+		if a.Type == InetAddressTypeIPv4z || a.Type == InetAddressTypeIPv6z {
+			var zone [4]byte
+			binary.BigEndian.PutUint32(zone[:], a.Zone)
+			octets = append(octets, zone[:]...)
+		}
+		return octets
+	case InetAddressTypeDNS:
+		return []byte(a.DNS)
+	}
+	return a.Raw
+}
+
+// Prefix returns the network with the given InetAddressPrefixLength
+func (a InetAddress) Prefix(length uint32) (*net.IPNet, error) {
+	if a.IP == nil {
+		return nil, fmt.Errorf("Address of type %s has no prefix", a.Type)
+	}
+	bits := 8 * len(a.IP)
+	if length > uint32(bits) {
+		return nil, fmt.Errorf("Prefix length %d exceeds %d bits", length, bits)
+	}
+	mask := net.CIDRMask(int(length), bits)
+	return &net.IPNet{IP: a.IP.Mask(mask), Mask: mask}, nil
+}
+
+// GetInetAddressTypedFormatted formats an InetAddress according to its
+// InetAddressType, which is inferred from the length if unknown
+func GetInetAddressTypedFormatted(value interface{}, addrType InetAddressType, flags Format) (v Value) {
+	v.Format = flags
+	bytes, ok := octetStringValue(value)
+	if !ok {
+		v.Raw = value
+		v.Err = invalidTypeError(value, "an InetAddress")
+		return
+	}
+	addr, err := NewInetAddress(addrType, bytes)
+	v.Raw = addr
+	v.Err = err
+	if flags&FormatString != 0 {
+		v.Formatted = addr.String()
+	}
+	return
+}
+
+// PairInetAddress formats the value of an InetAddress object according to the
+// value of the InetAddressType object that gives its context, as required by
+// RFC 4001. Without it, the type is inferred from the length, which misreads
+// dns addresses and cannot tell ipv4z from ipv6z. The value is returned
+// unchanged if either value is of the wrong type.
+func PairInetAddress(addr Value, addrType Value) Value {
+	var t InetAddressType
+	switch r := addrType.Raw.(type) {
+	case InetAddressType:
+		t = r
+	case int64:
+		t = InetAddressType(r)
+	default:
+		return addr
+	}
+	var octets []byte
+	if a, ok := addr.Raw.(InetAddress); ok {
+		octets = a.Octets()
+	} else if octets, ok = octetStringValue(addr.Raw); !ok {
+		return addr
+	}
+	paired := GetInetAddressTypedFormatted(octets, t, addr.Format)
+	paired.BaseType = addr.BaseType
+	paired.Units = addr.Units
+	return paired
+}
+
+// TimeStamp is the value of sysUpTime at which an event occurred, in
+// hundredths of a second
+type TimeStamp uint32
+
+func (t TimeStamp) Duration() time.Duration {
+	return time.Duration(t) * 10 * time.Millisecond
+}
+
+// Age returns how long ago the event occurred, given the current value of
+// sysUpTime, allowing for sysUpTime having wrapped around
+func (t TimeStamp) Age(sysUpTime uint32) time.Duration {
+	return time.Duration(sysUpTime-uint32(t)) * 10 * time.Millisecond
+}
+
+// Time returns the time at which the event occurred, given the value of
+// sysUpTime read at the time now
+func (t TimeStamp) Time(sysUpTime uint32, now time.Time) time.Time {
+	return now.Add(-t.Age(sysUpTime))
+}
+
+// GetTimeStampFormatted formats a TimeStamp as the uptime at which the event
+// occurred or, if the current sysUpTime is given, as the time since the event
+func GetTimeStampFormatted(value interface{}, flags Format, sysUpTime ...uint32) (v Value) {
+	v.Format = flags
+	intVal, err := ToInt64(value)
+	if err == nil && (intVal < 0 || intVal > math.MaxUint32) {
+		err = fmt.Errorf("Value %d overflows uint32", intVal)
+	}
+	if err != nil {
+		v.Raw = value
+		v.Err = fmt.Errorf("%w: %v", ErrInvalidType, err)
+		return
+	}
+	timeStamp := TimeStamp(intVal)
+	v.Raw = timeStamp
+	if flags == FormatNone {
+		return
+	}
+	d := timeStamp.Duration()
+	if len(sysUpTime) > 0 {
+		d = timeStamp.Age(sysUpTime[0])
+	}
+	if flags&FormatDurationShort > 0 {
+		v.Formatted = DurationFormat(d)
+	} else {
+		v.Formatted = DurationFormatLong(d)
+	}
+	if len(sysUpTime) > 0 {
+		v.Formatted += " ago"
+	}
+	return
+}
+
+// ParseDateAndTime decodes an 8 or 11 octet SNMPv2-TC DateAndTime. Without
+// the time zone, the time is returned in UTC.
+func ParseDateAndTime(octets []byte) (time.Time, error) {
+	if len(octets) != 8 && len(octets) != 11 {
+		return time.Time{}, fmt.Errorf("Invalid DateAndTime length %d", len(octets))
+	}
+	year := int(binary.BigEndian.Uint16(octets))
+	month, day, hour, minute, second, deci := octets[2], octets[3], octets[4], octets[5], octets[6], octets[7]
+	if month < 1 || month > 12 || day < 1 || day > 31 || hour > 23 || minute > 59 || second > 60 || deci > 9 {
+		return time.Time{}, errors.New("DateAndTime field out of range")
+	}
+	loc := time.UTC
+	if len(octets) == 11 {
+		direction, hours, minutes := octets[8], octets[9], octets[10]
+		if (direction != '+' && direction != '-') || hours > 13 || minutes > 59 {
+			return time.Time{}, errors.New("Invalid DateAndTime time zone")
+		}
+		offset := int(hours)*3600 + int(minutes)*60
+		if direction == '-' {
+			offset = -offset
+		}
+		loc = time.FixedZone("", offset)
+	}
+	return time.Date(year, time.Month(month), int(day), int(hour), int(minute), int(second), int(deci)*int(100*time.Millisecond), loc), nil
+}
+
+// EncodeDateAndTime encodes the time as an 11 octet SNMPv2-TC DateAndTime,
+// with the time zone of the time truncated to minutes. The year must be in
+// 0..65535.
+func EncodeDateAndTime(t time.Time) ([]byte, error) {
+	if t.Year() < 0 || t.Year() > 0xffff {
+		return nil, fmt.Errorf("%w: Year %d does not fit in a DateAndTime", ErrOutOfRange, t.Year())
+	}
+	_, offset := t.Zone()
+	direction := byte('+')
+	if offset < 0 {
+		direction, offset = '-', -offset
+	}
+	octets := make([]byte, 11)
+	binary.BigEndian.PutUint16(octets, uint16(t.Year()))
+	octets[2], octets[3] = byte(t.Month()), byte(t.Day())
+	octets[4], octets[5], octets[6] = byte(t.Hour()), byte(t.Minute()), byte(t.Second())
+	octets[7] = byte(t.Nanosecond() / int(100*time.Millisecond))
+	octets[8], octets[9], octets[10] = direction, byte(offset/3600), byte(offset%3600/60)
+	return octets, nil
+}
+
+// GetDateAndTimeFormatted formats a DateAndTime with its DISPLAY-HINT and
+// decodes it to a time.Time. Invalid values are formatted as octet strings.
+func GetDateAndTimeFormatted(value interface{}, flags Format, format string) Value {
+	v := GetOctetStringFormatted(value, flags, format)
+	if bytes, ok := octetStringValue(value); ok {
+		t, err := ParseDateAndTime(bytes)
+		if err != nil {
+			v.Err = err
+		} else {
+			v.Raw = t
+		}
+	}
+	return v
+}
+
+// Tags of the net-snmp Opaque-wrapped types
+const (
+	opaqueTagCounter64  = 0x76
+	opaqueTagFloat      = 0x78
+	opaqueTagDouble     = 0x79
+	opaqueTagInteger64  = 0x7a
+	opaqueTagUnsigned64 = 0x7b
+)
+
+// DecodeOpaque decodes the net-snmp types wrapped in an Opaque: float, double,
+// Counter64, Integer64 and Unsigned64
+func DecodeOpaque(octets []byte) (interface{}, error) {
+	if len(octets) < 3 || octets[0] != 0x9f {
+		return nil, errors.New("Opaque does not wrap a known type")
+	}
+	tag, length, data := octets[1], int(octets[2]), octets[3:]
+	if length != len(data) {
+		return nil, fmt.Errorf("Opaque length %d does not match %d octets of data", length, len(data))
+	}
+	switch tag {
+	case opaqueTagFloat:
+		if length != 4 {
+			return nil, fmt.Errorf("Invalid float length %d", length)
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(data)), nil
+	case opaqueTagDouble:
+		if length != 8 {
+			return nil, fmt.Errorf("Invalid double length %d", length)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(data)), nil
+	case opaqueTagCounter64, opaqueTagUnsigned64, opaqueTagInteger64:
+		if length == 0 || length > 9 {
+			return nil, fmt.Errorf("Invalid integer length %d", length)
+		}
+		var u uint64
+		for _, b := range data {
+			u = u<<8 | uint64(b)
+		}
+		if tag == opaqueTagInteger64 {
+			if data[0]&0x80 != 0 && length < 8 {
+				u |= uint64(math.MaxUint64) << (8 * uint(length))
+			}
+			return int64(u), nil
+		}
+		return u, nil
+	}
+	return nil, fmt.Errorf("Unknown Opaque tag 0x%02x", tag)
+}
+
+// EncodeOpaque is the inverse of DecodeOpaque. A uint64 is wrapped as an
+// Unsigned64 and an int64 as an Integer64.
+func EncodeOpaque(value interface{}) ([]byte, error) {
+	var tag byte
+	var data []byte
+	switch v := value.(type) {
+	case float32:
+		tag, data = opaqueTagFloat, make([]byte, 4)
+		binary.BigEndian.PutUint32(data, math.Float32bits(v))
+	case float64:
+		tag, data = opaqueTagDouble, make([]byte, 8)
+		binary.BigEndian.PutUint64(data, math.Float64bits(v))
+	case uint64:
+		tag, data = opaqueTagUnsigned64, make([]byte, 9)
+		binary.BigEndian.PutUint64(data[1:], v)
+		for len(data) > 1 && data[0] == 0 && data[1]&0x80 == 0 {
+			data = data[1:]
+		}
+	case int64:
+		tag, data = opaqueTagInteger64, make([]byte, 8)
+		binary.BigEndian.PutUint64(data, uint64(v))
+		for len(data) > 1 && ((data[0] == 0 && data[1]&0x80 == 0) || (data[0] == 0xff && data[1]&0x80 != 0)) {
+			data = data[1:]
+		}
+	default:
+		return nil, invalidTypeError(value, "an Opaque-wrapped")
+	}
+	return append([]byte{0x9f, tag, byte(len(data))}, data...), nil
+}
+
+// GetOpaqueFormatted decodes an Opaque-wrapped float, double or 64-bit
+// integer. Other values are formatted as octet strings.
+func GetOpaqueFormatted(value interface{}, flags Format) Value {
+	bytes, ok := octetStringValue(value)
+	if !ok {
+		return GetOctetStringFormatted(value, flags, "")
+	}
+	decoded, err := DecodeOpaque(bytes)
+	if err != nil {
+		return GetOctetStringFormatted(bytes, flags, "")
+	}
+	v := Value{Format: flags, Raw: decoded}
+	if flags&FormatString == 0 {
+		return v
+	}
+	switch d := decoded.(type) {
+	case float32:
+		v.Formatted = strconv.FormatFloat(float64(d), 'g', -1, 32)
+	case float64:
+		v.Formatted = strconv.FormatFloat(d, 'g', -1, 64)
+	default:
+		v.Formatted = fmt.Sprintf("%d", d)
+	}
+	return v
+}
@@ -0,0 +1,103 @@
+package models_test
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sleepinggenius2/gosmi/models"
+)
+
+func TestDateAndTimeWithTimeZone(t *testing.T) {
+	octets := []byte{0x07, 0xe8, 0x05, 0x1a, 0x0d, 0x1e, 0x0f, 0x03, '+', 0x05, 0x1e}
+	parsed, err := models.ParseDateAndTime(octets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2024, 5, 26, 8, 0, 15, int(300*time.Millisecond), time.UTC)
+	if _, offset := parsed.Zone(); !parsed.Equal(want) || offset != 5*3600+30*60 {
+		t.Errorf("ParseDateAndTime = %s, want %s at +05:30", parsed, want)
+	}
+	if encoded, err := models.EncodeDateAndTime(parsed); err != nil || !reflect.DeepEqual(encoded, octets) {
+		t.Errorf("EncodeDateAndTime = % x, %v, want % x", encoded, err, octets)
+	}
+	for _, year := range []int{-1, 65536} {
+		if _, err := models.EncodeDateAndTime(time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)); !errors.Is(err, models.ErrOutOfRange) {
+			t.Errorf("EncodeDateAndTime of year %d: got error %v, want ErrOutOfRange", year, err)
+		}
+	}
+	v := models.GetDateAndTimeFormatted(octets, models.FormatAll, "2d-1d-1d,1d:1d:1d.1d,1a1d:1d")
+	if raw, ok := v.Raw.(time.Time); !ok || !raw.Equal(want) || v.Formatted != "2024-5-26,13:30:15.3,+5:30" {
+		t.Errorf("GetDateAndTimeFormatted = %q with %#v", v.Formatted, v.Raw)
+	}
+}
+
+var opaqueTests = []struct {
+	source    string
+	octets    []byte
+	raw       interface{}
+	formatted string
+}{
+	{"float", []byte{0x9f, 0x78, 0x04, 0x3f, 0xc0, 0x00, 0x00}, float32(1.5), "1.5"},
+	{"double", []byte{0x9f, 0x79, 0x08, 0xc0, 0x09, 0x21, 0xfb, 0x54, 0x44, 0x2d, 0x18}, float64(-3.141592653589793), "-3.141592653589793"},
+	{"Unsigned64", []byte{0x9f, 0x7b, 0x09, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, uint64(18446744073709551615), "18446744073709551615"},
+	{"Integer64", []byte{0x9f, 0x7a, 0x01, 0xff}, int64(-1), "-1"},
+}
+
+func TestOpaque(t *testing.T) {
+	for _, test := range opaqueTests {
+		v := models.GetOpaqueFormatted(test.octets, models.FormatAll)
+		if !reflect.DeepEqual(v.Raw, test.raw) || v.Formatted != test.formatted {
+			t.Errorf("%s: GetOpaqueFormatted = %q with %#v, want %q with %#v", test.source, v.Formatted, v.Raw, test.formatted, test.raw)
+		}
+		if encoded, err := models.EncodeOpaque(test.raw); err != nil || !reflect.DeepEqual(encoded, test.octets) {
+			t.Errorf("%s: EncodeOpaque = % x, %v, want % x", test.source, encoded, err, test.octets)
+		}
+	}
+}
+
+func TestInetAddressIPv6z(t *testing.T) {
+	octets := []byte{0xfe, 0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 4}
+	for _, addrType := range []models.InetAddressType{models.InetAddressTypeIPv6z, models.InetAddressTypeUnknown} {
+		v := models.GetInetAddressTypedFormatted(octets, addrType, models.FormatAll)
+		addr, ok := v.Raw.(models.InetAddress)
+		if !ok || v.Err != nil || addr.Type != models.InetAddressTypeIPv6z || !addr.IP.Equal(net.ParseIP("fe80::1")) || addr.Zone != 4 {
+			t.Errorf("%s: got %#v with error %v", addrType, v.Raw, v.Err)
+			continue
+		}
+		if v.Formatted != "fe80::1%4" || !reflect.DeepEqual(addr.Octets(), octets) {
+			t.Errorf("%s: formatted %q with octets % x", addrType, v.Formatted, addr.Octets())
+		}
+	}
+}
+
+func TestPairInetAddress(t *testing.T) {
+	// A DNS name of 8 octets is inferred to be ipv4z
+	addr := models.GetInetAddressTypedFormatted([]byte("peer.net"), models.InetAddressTypeUnknown, models.FormatAll)
+	addrType := models.Value{Raw: models.InetAddressTypeDNS}
+	paired := models.PairInetAddress(addr, addrType)
+	if raw, ok := paired.Raw.(models.InetAddress); !ok || raw.Type != models.InetAddressTypeDNS || raw.DNS != "peer.net" || paired.Formatted != "peer.net" {
+		t.Errorf("PairInetAddress = %q with %#v", paired.Formatted, paired.Raw)
+	}
+	if unpaired := models.PairInetAddress(addr, models.Value{Raw: "dns"}); !reflect.DeepEqual(unpaired, addr) {
+		t.Errorf("PairInetAddress with a string type = %#v, want it unchanged", unpaired)
+	}
+}
+
+func TestTimeStampAge(t *testing.T) {
+	for _, test := range []struct {
+		stamp     models.TimeStamp
+		sysUpTime uint32
+		age       time.Duration
+	}{
+		{100, 600, 5 * time.Second},
+		{4294967196, 400, 5 * time.Second}, // sysUpTime wrapped after the event
+		{0, 0, 0},
+	} {
+		if age := test.stamp.Age(test.sysUpTime); age != test.age {
+			t.Errorf("TimeStamp(%d).Age(%d) = %s, want %s", test.stamp, test.sysUpTime, age, test.age)
+		}
+	}
+}
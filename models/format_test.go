@@ -0,0 +1,146 @@
+package models_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+func TestToInt64(t *testing.T) {
+	for _, value := range []interface{}{uint64(math.MaxInt64 + 1), uint64(math.MaxUint64), uint(math.MaxInt64 + 1)} {
+		if i, err := models.ToInt64(value); err == nil {
+			t.Errorf("ToInt64(%d) = %d, want error", value, i)
+		}
+	}
+	if i, err := models.ToInt64(uint64(math.MaxInt64)); err != nil || i != math.MaxInt64 {
+		t.Errorf("ToInt64(MaxInt64) = %d, %v", i, err)
+	}
+}
+
+func TestGetIntFormattedUnsigned(t *testing.T) {
+	for _, value := range []interface{}{uint64(math.MaxUint64), uint(1 << 63), uint64(5)} {
+		v := models.GetIntFormatted(value, models.FormatAll, "")
+		if _, ok := v.Raw.(uint64); !ok || v.Err != nil {
+			t.Errorf("GetIntFormatted(%d) = %#v with error %v, want uint64", value, v.Raw, v.Err)
+		}
+	}
+	v := models.GetIntFormatted(uint64(math.MaxUint64), models.FormatAll, "d-2")
+	if v.Formatted != "184467440737095516.15" {
+		t.Errorf("GetIntFormatted(MaxUint64, d-2) = %q, want 184467440737095516.15", v.Formatted)
+	}
+}
+
+func TestUnsigned64Range(t *testing.T) {
+	r := models.Range{BaseType: types.BaseTypeUnsigned64, MinValue: 1 << 62, MaxValue: math.MaxInt64, MinUnsigned: 1 << 62, MaxUnsigned: math.MaxUint64 - 1}
+	if r.String() != "4611686018427387904..18446744073709551614" {
+		t.Errorf("Range = %s", r)
+	}
+	for value, contains := range map[uint64]bool{1 << 62: true, 1 << 63: true, math.MaxUint64 - 1: true, 1<<62 - 1: false, math.MaxUint64: false} {
+		if r.Contains(value) != contains {
+			t.Errorf("Range %s contains %d = %t, want %t", r, value, !contains, contains)
+		}
+	}
+	typ := models.Type{BaseType: types.BaseTypeUnsigned64}
+	if err := typ.Validate(uint64(math.MaxUint64)); err != nil {
+		t.Errorf("Unsigned64 without ranges rejected MaxUint64: %v", err)
+	}
+	if err := typ.Validate(-1); !errors.Is(err, models.ErrInvalidType) {
+		t.Errorf("Unsigned64 accepted -1: %v", err)
+	}
+}
+
+func TestToOid(t *testing.T) {
+	tests := []struct {
+		source string
+		value  interface{}
+		oid    string
+	}{
+		{"Oid", types.Oid{1, 3, 6, 1}, "1.3.6.1"},
+		{"SmiSubId slice", []types.SmiSubId{1, 3, 6, 1}, "1.3.6.1"},
+		{"uint32 slice", []uint32{1, 3, 4294967295}, "1.3.4294967295"},
+		{"int slice", []int{1, 3, 6, 1}, "1.3.6.1"},
+		{"String", "1.3.6.1", "1.3.6.1"},
+		{"String with leading dot", ".1.3.6.1", "1.3.6.1"},
+		{"Negative int", []int{1, -3}, ""},
+		{"Int overflow", []int{1, 4294967296}, ""},
+		{"Invalid string", "1.3.x", ""},
+		{"Invalid type", 1.3, ""},
+	}
+	for _, test := range tests {
+		oid, err := models.ToOid(test.value)
+		if test.oid == "" {
+			if err == nil {
+				t.Errorf("%s: ToOid(%v) = %s, want error", test.source, test.value, oid)
+			}
+			continue
+		}
+		if err != nil || oid.String() != test.oid {
+			t.Errorf("%s: ToOid(%v) = %s, %v, want %s", test.source, test.value, oid, err, test.oid)
+		}
+	}
+}
+
+func TestGetOctetStringFormatted(t *testing.T) {
+	tests := []struct {
+		source    string
+		value     interface{}
+		format    string
+		formatted string
+	}{
+		{"Bytes", []byte{0x0a, 0x0b}, "1x:", "0a:0b"},
+		{"Ints", []int{10, 11}, "1x:", "0a:0b"},
+		{"String", "AB", "1x:", "41:42"},
+		{"String without hint", "eth0", "", "65 74 68 30"},
+		{"IPv4 InetAddress", []byte{0x04, 192, 0, 2, 1}, "InetAddress", "192.0.2.1"},
+		{"IPv4 IpV4orV6Addr", "\xc0\x00\x02\x01", "IpV4orV6Addr", "192.0.2.1"},
+	}
+	for _, test := range tests {
+		v := models.GetOctetStringFormatted(test.value, models.FormatAll, test.format)
+		if v.Err != nil || v.Formatted != test.formatted {
+			t.Errorf("%s: GetOctetStringFormatted(%v, %q) = %q with error %v, want %q", test.source, test.value, test.format, v.Formatted, v.Err, test.formatted)
+		}
+		if _, ok := v.Raw.([]byte); !ok {
+			t.Errorf("%s: GetOctetStringFormatted(%v, %q) has raw %T, want []byte", test.source, test.value, test.format, v.Raw)
+		}
+	}
+}
+
+func TestFormattedErrors(t *testing.T) {
+	enum := &models.Enum{BaseType: types.BaseTypeEnum, Values: []models.NamedNumber{{Name: "up", Value: 1}, {Name: "down", Value: 2}}}
+	tests := []struct {
+		source string
+		value  models.Value
+		err    error
+	}{
+		{"Int", models.GetIntFormatted("five", models.FormatAll, ""), models.ErrInvalidType},
+		{"Uint", models.GetUintFormatted(-1, models.FormatAll, ""), models.ErrInvalidType},
+		{"Duration", models.GetDurationFormatted([]byte{1}, models.FormatAll), models.ErrInvalidType},
+		{"Enum", models.GetEnumFormatted(1.5, models.FormatAll, enum), models.ErrInvalidType},
+		{"Enum name", models.GetEnumFormatted("testing", models.FormatAll, enum), models.ErrUnknownEnum},
+		{"Bits", models.GetBitsFormatted(7, models.FormatAll), models.ErrInvalidType},
+		{"Enum bits", models.GetEnumBitsFormatted(7, models.FormatAll, enum), models.ErrInvalidType},
+		{"Octet string", models.GetOctetStringFormatted(7, models.FormatAll, ""), models.ErrInvalidType},
+		{"Octet string out of range", models.GetOctetStringFormatted([]int{256}, models.FormatAll, ""), models.ErrInvalidType},
+		{"InetAddress", models.GetInetAddressFormatted(7, models.FormatAll), models.ErrInvalidType},
+		{"Object identifier", models.GetObjectIdentifierFormatted(7, models.FormatAll), models.ErrInvalidType},
+		{"TimeStamp", models.GetTimeStampFormatted(int64(1)<<32, models.FormatAll), models.ErrInvalidType},
+	}
+	for _, test := range tests {
+		if !errors.Is(test.value.Err, test.err) {
+			t.Errorf("%s: got error %v, want %v", test.source, test.value.Err, test.err)
+		}
+		// The value is kept as given
+		if test.value.Raw == nil {
+			t.Errorf("%s: raw value is nil", test.source)
+		}
+	}
+	if v := models.GetEnumFormatted("down", models.FormatAll, enum); v.Err != nil || v.Raw != int64(2) {
+		t.Errorf("Enum name down = %v with error %v, want 2", v.Raw, v.Err)
+	}
+	if v := models.GetDateAndTimeFormatted([]byte{7, 0xe2, 13, 1, 0, 0, 0, 0}, models.FormatAll, ""); v.Err == nil {
+		t.Errorf("DateAndTime with month 13 = %v, want error", v.Raw)
+	}
+}
@@ -0,0 +1,261 @@
+package models
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// ValueJSONVersion is the version of the JSON encoding of Value
+const ValueJSONVersion = 1
+
+// Names of the Go types of the raw value in the JSON encoding of Value
+const (
+	rawTypeInt64           = "int64"
+	rawTypeUint64          = "uint64"
+	rawTypeUint32          = "uint32"
+	rawTypeFloat32         = "float32"
+	rawTypeFloat64         = "float64"
+	rawTypeBool            = "bool"
+	rawTypeOctets          = "octets"
+	rawTypeString          = "string"
+	rawTypeHardwareAddr    = "hardwareAddr"
+	rawTypeOid             = "oid"
+	rawTypeDuration        = "duration"
+	rawTypeTime            = "time"
+	rawTypeTimeStamp       = "timeStamp"
+	rawTypeRowStatus       = "rowStatus"
+	rawTypeStorageType     = "storageType"
+	rawTypeInetAddressType = "inetAddressType"
+	rawTypeInetAddress     = "inetAddress"
+	rawTypeJSON            = "json"
+)
+
+type valueJSON struct {
+	Version   int             `json:"version"`
+	BaseType  types.BaseType  `json:"baseType"`
+	Format    uint8           `json:"format"`
+	RawType   string          `json:"rawType,omitempty"`
+	Raw       json.RawMessage `json:"raw,omitempty"`
+	Formatted string          `json:"formatted"`
+	Label     string          `json:"label,omitempty"`
+	Bits      []string        `json:"bits,omitempty"`
+	Units     string          `json:"units,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+type inetAddressJSON struct {
+	Type   InetAddressType `json:"type"`
+	Octets string          `json:"octets"`
+}
+
+// MarshalJSON encodes the value as a versioned JSON object. The raw value is
+// encoded in a canonical form tagged with its Go type: octets as hex, 64-bit
+// integers as decimal strings, OIDs in dotted notation, durations as Go
+// duration strings and times in RFC 3339 format. Raw values of other types,
+// such as those returned by registered formatters, are encoded as plain
+// JSON and are not decoded back to their Go type.
+func (v Value) MarshalJSON() ([]byte, error) {
+	out := valueJSON{
+		Version:   ValueJSONVersion,
+		BaseType:  v.BaseType,
+		Format:    uint8(v.Format),
+		Formatted: v.Formatted,
+		Label:     v.Label,
+		Bits:      v.Bits,
+		Units:     v.Units,
+	}
+	if v.Err != nil {
+		out.Error = v.Err.Error()
+	}
+	var raw interface{}
+	is64Bit := v.BaseType == types.BaseTypeInteger64 || v.BaseType == types.BaseTypeUnsigned64
+	switch r := v.Raw.(type) {
+	case nil:
+	case int64:
+		out.RawType, raw = rawTypeInt64, r
+		if is64Bit {
+			raw = strconv.FormatInt(r, 10)
+		}
+	case uint64:
+		out.RawType, raw = rawTypeUint64, strconv.FormatUint(r, 10)
+	case uint32:
+		out.RawType, raw = rawTypeUint32, r
+	case float32:
+		out.RawType, raw = rawTypeFloat32, strconv.FormatFloat(float64(r), 'g', -1, 32)
+	case float64:
+		out.RawType, raw = rawTypeFloat64, strconv.FormatFloat(r, 'g', -1, 64)
+	case bool:
+		out.RawType, raw = rawTypeBool, r
+	case []byte:
+		out.RawType, raw = rawTypeOctets, hex.EncodeToString(r)
+	case string:
+		out.RawType, raw = rawTypeString, r
+	case net.HardwareAddr:
+		out.RawType, raw = rawTypeHardwareAddr, hex.EncodeToString(r)
+	case types.Oid:
+		out.RawType, raw = rawTypeOid, r.String()
+	case time.Duration:
+		out.RawType, raw = rawTypeDuration, r.String()
+	case time.Time:
+		out.RawType, raw = rawTypeTime, r.Format(time.RFC3339Nano)
+	case TimeStamp:
+		out.RawType, raw = rawTypeTimeStamp, uint32(r)
+	case RowStatus:
+		out.RawType, raw = rawTypeRowStatus, int64(r)
+	case StorageType:
+		out.RawType, raw = rawTypeStorageType, int64(r)
+	case InetAddressType:
+		out.RawType, raw = rawTypeInetAddressType, int64(r)
+	case InetAddress:
+		out.RawType, raw = rawTypeInetAddress, inetAddressJSON{Type: r.Type, Octets: hex.EncodeToString(r.Octets())}
+	default:
+		out.RawType, raw = rawTypeJSON, r
+	}
+	if out.RawType != "" {
+		var err error
+		if out.Raw, err = json.Marshal(raw); err != nil {
+			return nil, fmt.Errorf("Marshal raw value: %w", err)
+		}
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a value encoded by MarshalJSON, reconstructing the
+// raw value with its original Go type. The error, if any, is restored with
+// its message only.
+func (v *Value) UnmarshalJSON(data []byte) error {
+	var in valueJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	if in.Version < 1 || in.Version > ValueJSONVersion {
+		return fmt.Errorf("Unsupported Value JSON version %d", in.Version)
+	}
+	raw, err := unmarshalRaw(in.RawType, in.Raw)
+	if err != nil {
+		return fmt.Errorf("Unmarshal raw %s value: %w", in.RawType, err)
+	}
+	*v = Value{
+		BaseType:  in.BaseType,
+		Format:    Format(in.Format),
+		Formatted: in.Formatted,
+		Raw:       raw,
+		Label:     in.Label,
+		Bits:      in.Bits,
+		Units:     in.Units,
+	}
+	if in.Error != "" {
+		v.Err = errors.New(in.Error)
+	}
+	return nil
+}
+
+// unmarshalNumber decodes a JSON number or a decimal string
+func unmarshalNumber(data json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return s, nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return "", err
+	}
+	return n.String(), nil
+}
+
+func unmarshalRaw(rawType string, data json.RawMessage) (interface{}, error) {
+	switch rawType {
+	case "":
+		return nil, nil
+	case rawTypeJSON:
+		var raw interface{}
+		err := json.Unmarshal(data, &raw)
+		return raw, err
+	case rawTypeBool:
+		var b bool
+		err := json.Unmarshal(data, &b)
+		return b, err
+	case rawTypeInetAddress:
+		var a inetAddressJSON
+		if err := json.Unmarshal(data, &a); err != nil {
+			return nil, err
+		}
+		octets, err := hex.DecodeString(a.Octets)
+		if err != nil {
+			return nil, err
+		}
+		addr, _ := NewInetAddress(a.Type, octets)
+		if addr.Type != a.Type {
+			// An unknown type is inferred from the length, so keep it unknown
+			addr = InetAddress{Type: a.Type, Raw: octets}
+		}
+		return addr, nil
+	case rawTypeInt64, rawTypeUint64, rawTypeUint32, rawTypeTimeStamp, rawTypeRowStatus, rawTypeStorageType, rawTypeInetAddressType:
+		s, err := unmarshalNumber(data)
+		if err != nil {
+			return nil, err
+		}
+		switch rawType {
+		case rawTypeUint64:
+			return strconv.ParseUint(s, 10, 64)
+		case rawTypeUint32, rawTypeTimeStamp:
+			u, err := strconv.ParseUint(s, 10, 32)
+			if rawType == rawTypeTimeStamp {
+				return TimeStamp(u), err
+			}
+			return uint32(u), err
+		}
+		i, err := strconv.ParseInt(s, 10, 64)
+		switch rawType {
+		case rawTypeRowStatus:
+			return RowStatus(i), err
+		case rawTypeStorageType:
+			return StorageType(i), err
+		case rawTypeInetAddressType:
+			return InetAddressType(i), err
+		}
+		return i, err
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	switch rawType {
+	case rawTypeString:
+		return s, nil
+	case rawTypeOctets:
+		return hex.DecodeString(s)
+	case rawTypeHardwareAddr:
+		b, err := hex.DecodeString(s)
+		return net.HardwareAddr(b), err
+	case rawTypeOid:
+		if s == "" {
+			return types.Oid{}, nil
+		}
+		return types.OidFromString(s)
+	case rawTypeDuration:
+		return time.ParseDuration(s)
+	case rawTypeTime:
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil || t.Location() == time.UTC {
+			return t, err
+		}
+		// Parse uses the local time zone if the offset matches it, so fix the
+		// zone as ParseDateAndTime does
+		_, offset := t.Zone()
+		return t.In(time.FixedZone("", offset)), nil
+	case rawTypeFloat32:
+		f, err := strconv.ParseFloat(s, 32)
+		return float32(f), err
+	case rawTypeFloat64:
+		return strconv.ParseFloat(s, 64)
+	}
+	return nil, fmt.Errorf("Unknown raw type %q", rawType)
+}
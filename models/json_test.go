@@ -0,0 +1,88 @@
+package models_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+func mustInetAddress(addrType models.InetAddressType, octets []byte) models.InetAddress {
+	addr, err := models.NewInetAddress(addrType, octets)
+	if err != nil {
+		panic(err)
+	}
+	return addr
+}
+
+var jsonTests = []struct {
+	source   string
+	baseType types.BaseType
+	raw      interface{}
+}{
+	{"Octets", types.BaseTypeOctetString, []byte{0x00, 0xff, 'a'}},
+	{"Counter64 above MaxInt64", types.BaseTypeUnsigned64, uint64(1<<63 + 1)},
+	{"OID", types.BaseTypeObjectIdentifier, types.Oid{1, 3, 6, 1, 2, 1, 2, 2, 1, 8}},
+	{"DateAndTime with time zone", types.BaseTypeOctetString, time.Date(2024, 5, 26, 13, 30, 15, int(100*time.Millisecond), time.FixedZone("", -4*60*60))},
+	{"InetAddress with zone", types.BaseTypeOctetString, mustInetAddress(models.InetAddressTypeIPv6z, []byte{0xfe, 0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 4})},
+	{"TimeStamp", types.BaseTypeUnsigned32, models.TimeStamp(4294967295)},
+	{"RowStatus", types.BaseTypeEnum, models.RowStatusCreateAndWait},
+	{"InetAddressType", types.BaseTypeEnum, models.InetAddressTypeIPv4z},
+}
+
+func TestValueJSON(t *testing.T) {
+	for i, test := range jsonTests {
+		in := models.Value{
+			BaseType:  test.baseType,
+			Format:    models.FormatAll,
+			Raw:       test.raw,
+			Formatted: "formatted " + test.source,
+			Label:     "label",
+			Bits:      []string{"alpha", "gamma"},
+			Units:     "seconds",
+		}
+		if i%2 == 1 {
+			in.Err = fmt.Errorf("%w: %s", models.ErrInvalidType, test.source)
+		}
+		data, err := json.Marshal(in)
+		if err != nil {
+			t.Errorf("%s: Marshal: %v", test.source, err)
+			continue
+		}
+		var version struct {
+			Version int `json:"version"`
+		}
+		if err := json.Unmarshal(data, &version); err != nil || version.Version != models.ValueJSONVersion {
+			t.Errorf("%s: Marshal = %s, want version %d", test.source, data, models.ValueJSONVersion)
+		}
+		var out models.Value
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Errorf("%s: Unmarshal(%s): %v", test.source, data, err)
+			continue
+		}
+		if !reflect.DeepEqual(out.Raw, test.raw) {
+			t.Errorf("%s: Unmarshal(%s) = %#v, want %#v", test.source, data, out.Raw, test.raw)
+		}
+		if out.BaseType != in.BaseType || out.Format != in.Format || out.Formatted != in.Formatted || out.Label != in.Label || !reflect.DeepEqual(out.Bits, in.Bits) || out.Units != in.Units {
+			t.Errorf("%s: Unmarshal(%s) = %+v, want %+v", test.source, data, out, in)
+		}
+		if (out.Err == nil) != (in.Err == nil) || (in.Err != nil && out.Err.Error() != in.Err.Error()) {
+			t.Errorf("%s: Unmarshal(%s) error = %v, want %v", test.source, data, out.Err, in.Err)
+		}
+	}
+}
+
+func TestValueJSONVersion(t *testing.T) {
+	for _, version := range []string{"0", "2"} {
+		var v models.Value
+		err := json.Unmarshal([]byte(`{"version":`+version+`,"baseType":"Integer32","format":0,"formatted":""}`), &v)
+		if err == nil || !strings.Contains(err.Error(), "Unsupported") {
+			t.Errorf("Version %s: got error %v, want unsupported version", version, err)
+		}
+	}
+}
@@ -0,0 +1,486 @@
+package models
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// ParseError reports the offset in the input at which parsing a formatted
+// value failed
+type ParseError struct {
+	Input  string
+	Offset int
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	if e.Offset >= len(e.Input) {
+		return fmt.Sprintf("Parse %q at end of input: %v", e.Input, e.Err)
+	}
+	return fmt.Sprintf("Parse %q at offset %d (%q): %v", e.Input, e.Offset, e.Input[e.Offset:], e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func parseErrorf(input string, offset int, format string, a ...interface{}) error {
+	return &ParseError{Input: input, Offset: offset, Err: fmt.Errorf(format, a...)}
+}
+
+func (n Node) ParseValue(s string) (interface{}, error) {
+	return n.Type.ParseValue(s)
+}
+
+func (n ScalarNode) ParseValue(s string) (interface{}, error) {
+	return n.Type.ParseValue(s)
+}
+
+func (n ColumnNode) ParseValue(s string) (interface{}, error) {
+	return n.Type.ParseValue(s)
+}
+
+func parseIPv4Address(s string) (interface{}, error) {
+	return ParseInetAddress(s, true)
+}
+
+func parseIPAddress(s string) (interface{}, error) {
+	return ParseInetAddress(s, false)
+}
+
+func parseTimeTicks(s string) (interface{}, error) {
+	return ParseTimeTicks(s)
+}
+
+func inetAddressParser(addrType InetAddressType) ValueParser {
+	return func(s string) (interface{}, error) {
+		return ParseInetAddressTyped(s, addrType)
+	}
+}
+
+// tcParsers are the inverses of the tcFormatters whose formatted values do
+// not follow from the base type and DISPLAY-HINT, keyed by module-qualified
+// identity
+var tcParsers = map[string]ValueParser{
+	"RFC1065-SMI::IpAddress":  parseIPv4Address,
+	"RFC1155-SMI::IpAddress":  parseIPv4Address,
+	"SNMPv2-SMI::IpAddress":   parseIPv4Address,
+	"RFC1065-SMI::TimeTicks":  parseTimeTicks,
+	"RFC1155-SMI::TimeTicks":  parseTimeTicks,
+	"SNMPv2-SMI::TimeTicks":   parseTimeTicks,
+	"SNMPv2-TC::TimeStamp":    parseTimeTicks,
+	"SNMPv2-TC::TimeInterval": parseTimeTicks,
+
+	"INET-ADDRESS-MIB::InetAddress":      inetAddressParser(InetAddressTypeUnknown),
+	"INET-ADDRESS-MIB::InetAddressIPv4":  inetAddressParser(InetAddressTypeIPv4),
+	"INET-ADDRESS-MIB::InetAddressIPv6":  inetAddressParser(InetAddressTypeIPv6),
+	"INET-ADDRESS-MIB::InetAddressIPv4z": inetAddressParser(InetAddressTypeIPv4z),
+	"INET-ADDRESS-MIB::InetAddressIPv6z": inetAddressParser(InetAddressTypeIPv6z),
+	"INET-ADDRESS-MIB::InetAddressDNS":   inetAddressParser(InetAddressTypeDNS),
+}
+
+// legacyParsers are used by bare name for types without a module
+var legacyParsers = map[string]ValueParser{
+	"IpAddress":    parseIPv4Address,
+	"InetAddress":  parseIPAddress,
+	"IpV4orV6Addr": parseIPAddress,
+	"TimeTicks":    parseTimeTicks,
+	"TimeInterval": parseTimeTicks,
+	"TimeStamp":    parseTimeTicks,
+}
+
+// getTCParser returns the built-in parser for the first type in the
+// derivation chain that has one
+func (t Type) getTCParser() ValueParser {
+	for _, identity := range t.identities() {
+		parsers := tcParsers
+		if !strings.Contains(identity, "::") {
+			parsers = legacyParsers
+		}
+		if parser, ok := parsers[identity]; ok {
+			return parser
+		}
+	}
+	return nil
+}
+
+// ParseValue is the inverse of FormatValue. It parses a formatted value into
+// the raw value that FormatValue accepts: []byte for octet strings and BITS,
+// types.Oid for object identifiers, uint64 for unsigned 64-bit integers and
+// int64 for everything else, with TimeTicks in hundredths of a second. A
+// parser registered for the type, or a type it is derived from, takes
+// precedence.
+func (t Type) ParseValue(s string) (interface{}, error) {
+	if parser := t.getRegisteredParser(); parser != nil {
+		return parser(s)
+	}
+	if t.Units != "" {
+		s = strings.TrimSuffix(s, " "+t.Units)
+	}
+	if parser := t.getTCParser(); parser != nil {
+		return parser(s)
+	}
+	switch t.BaseType {
+	case types.BaseTypeOctetString:
+		return ParseStringDisplayHint(t.Format, s)
+	case types.BaseTypeBits:
+		return ParseBits(s, t.Enum)
+	case types.BaseTypeEnum:
+		return ParseEnum(s, t.Enum)
+	case types.BaseTypeObjectIdentifier:
+		oid, err := types.OidFromString(strings.TrimPrefix(s, "."))
+		if err != nil {
+			return nil, &ParseError{Input: s, Err: err}
+		}
+		return oid, nil
+	case types.BaseTypeUnsigned64:
+		return ParseUnsignedDisplayHint(t.Format, s)
+	}
+	return ParseIntegerDisplayHint(t.Format, s)
+}
+
+func isRadixDigit(c byte, radix int) bool {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c-'0') < radix
+	case c >= 'a' && c <= 'f', c >= 'A' && c <= 'F':
+		return radix == 16
+	}
+	return false
+}
+
+func parseHexOctets(s string) ([]byte, error) {
+	out := make([]byte, 0, len(s)/2)
+	for pos := 0; pos < len(s); {
+		switch s[pos] {
+		case ' ', ':', '-':
+			pos++
+			continue
+		}
+		if pos+1 >= len(s) || !isRadixDigit(s[pos], 16) || !isRadixDigit(s[pos+1], 16) {
+			return nil, parseErrorf(s, pos, "Expected two hexadecimal digits")
+		}
+		value, _ := strconv.ParseUint(s[pos:pos+2], 16, 8)
+		out = append(out, byte(value))
+		pos += 2
+	}
+	return out, nil
+}
+
+// ParseStringDisplayHint is the inverse of StringDisplayHint. Without a
+// DISPLAY-HINT, the input is expected to be hexadecimal octets, as formatted,
+// or a double-quoted string.
+func ParseStringDisplayHint(format string, s string) ([]byte, error) {
+	switch format {
+	case "InetAddress", "IpV4orV6Addr":
+		return ParseInetAddress(s, false)
+	}
+	return getDisplayHint(format).Parse(s)
+}
+
+// ParseInetAddress parses an IPv4 or IPv6 address. If v4Only is set, only
+// IPv4 addresses are accepted.
+func ParseInetAddress(s string, v4Only bool) ([]byte, error) {
+	ip := net.ParseIP(strings.TrimSpace(s))
+	if ip == nil {
+		return nil, parseErrorf(s, 0, "Invalid IP address")
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return []byte(ip4), nil
+	}
+	if v4Only {
+		return nil, parseErrorf(s, 0, "Expected IPv4 address")
+	}
+	return []byte(ip.To16()), nil
+}
+
+// ParseInetAddressTyped is the inverse of GetInetAddressTypedFormatted. It
+// parses an address of the given InetAddressType, with a %zone suffix for
+// ipv4z and ipv6z, to its octets. For InetAddressTypeUnknown, the type is
+// inferred from the form of the input: space-separated hexadecimal octets, an
+// IP address with an optional zone, or otherwise a DNS name.
+func ParseInetAddressTyped(s string, addrType InetAddressType) ([]byte, error) {
+	str := strings.TrimSpace(s)
+	switch addrType {
+	case InetAddressTypeDNS:
+		return []byte(str), nil
+	case InetAddressTypeUnknown:
+		if str == "" || strings.Contains(str, " ") {
+			return parseHexOctets(s)
+		}
+		if !strings.ContainsAny(str, ":%") && net.ParseIP(str) == nil {
+			return []byte(str), nil
+		}
+	case InetAddressTypeIPv4, InetAddressTypeIPv6, InetAddressTypeIPv4z, InetAddressTypeIPv6z:
+	default:
+		return parseHexOctets(s)
+	}
+	addr, zone, zoned := str, uint64(0), false
+	if i := strings.LastIndexByte(str, '%'); i >= 0 {
+		var err error
+		if zone, err = strconv.ParseUint(str[i+1:], 10, 32); err != nil {
+			return nil, parseErrorf(s, i+1, "Invalid zone index")
+		}
+		addr, zoned = str[:i], true
+	}
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return nil, parseErrorf(s, 0, "Invalid IP address")
+	}
+	ip4 := ip.To4()
+	if strings.Contains(addr, ":") {
+		ip4 = nil
+	}
+	switch addrType {
+	case InetAddressTypeIPv4, InetAddressTypeIPv4z:
+		if ip4 == nil {
+			return nil, parseErrorf(s, 0, "Expected IPv4 address")
+		}
+	case InetAddressTypeIPv6, InetAddressTypeIPv6z:
+		if ip4 != nil {
+			return nil, parseErrorf(s, 0, "Expected IPv6 address")
+		}
+	}
+	switch addrType {
+	case InetAddressTypeIPv4, InetAddressTypeIPv6:
+		if zoned {
+			return nil, parseErrorf(s, len(addr), "Unexpected zone index for %s address", addrType)
+		}
+	case InetAddressTypeIPv4z, InetAddressTypeIPv6z:
+		if !zoned {
+			return nil, parseErrorf(s, len(str), "Expected zone index for %s address", addrType)
+		}
+	}
+	octets := []byte(ip.To16())
+	if ip4 != nil {
+		octets = []byte(ip4)
+	}
+	if zoned {
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], uint32(zone))
+		octets = append(octets, b[:]...)
+	}
+	return octets, nil
+}
+
+type labelToken struct {
+	value  string
+	offset int
+}
+
+func splitLabels(s string, offset int) (tokens []labelToken) {
+	start := -1
+	for i := 0; i <= len(s); i++ {
+		if i == len(s) || strings.IndexByte(" \t,|{}", s[i]) >= 0 {
+			if start >= 0 {
+				tokens = append(tokens, labelToken{value: s[start:i], offset: offset + start})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	return
+}
+
+// parseLabel parses a label of the form name, name(number) or number
+func parseLabel(input string, token labelToken, enum *Enum) (int64, error) {
+	label := token.value
+	if value, err := strconv.ParseInt(label, 10, 64); err == nil {
+		return value, nil
+	}
+	var number string
+	if i := strings.IndexByte(label, '('); i >= 0 {
+		if !strings.HasSuffix(label, ")") {
+			return 0, parseErrorf(input, token.offset+len(label), "Expected ')'")
+		}
+		label, number = label[:i], label[i+1:len(label)-1]
+	}
+	if enum == nil {
+		return 0, parseErrorf(input, token.offset, "Unknown label %q", label)
+	}
+	value, err := enum.Value(label)
+	if err != nil {
+		if number == "" || label != "unknown" {
+			return 0, parseErrorf(input, token.offset, "Unknown label %q", label)
+		}
+	}
+	if number != "" {
+		numberValue, err := strconv.ParseInt(number, 10, 64)
+		if err != nil {
+			return 0, parseErrorf(input, token.offset+len(label)+1, "Invalid number %q", number)
+		}
+		if label != "unknown" && numberValue != value {
+			return 0, parseErrorf(input, token.offset+len(label)+1, "Number %d does not match %s(%d)", numberValue, label, value)
+		}
+		value = numberValue
+	}
+	return value, nil
+}
+
+// ParseEnum parses an enumeration value given as name, name(number) or number
+func ParseEnum(s string, enum *Enum) (int64, error) {
+	tokens := splitLabels(s, 0)
+	if len(tokens) != 1 {
+		return 0, parseErrorf(s, 0, "Expected a single enumeration label")
+	}
+	return parseLabel(s, tokens[0], enum)
+}
+
+// ParseBits is the inverse of GetEnumBitsFormatted and GetBitsFormatted. It
+// accepts bit labels, such as "a b", "{ a, b }" or "a(0) b(1)", bit positions,
+// or the hexadecimal octets.
+func ParseBits(s string, enum *Enum) ([]byte, error) {
+	inner, offset := s, 0
+	if i := strings.IndexByte(s, '['); i >= 0 && strings.HasSuffix(s, "]") {
+		inner, offset = s[i+1:len(s)-1], i+1
+	}
+	if enum == nil {
+		return parseHexOctets(s)
+	}
+	bits := make([]byte, 0)
+	for _, token := range splitLabels(inner, offset) {
+		bit, err := parseLabel(s, token, enum)
+		if err != nil {
+			return nil, err
+		}
+		if bit < 0 || bit > math.MaxUint16 {
+			return nil, parseErrorf(s, token.offset, "Bit position %d out of range", bit)
+		}
+		bits = setBit(bits, bit)
+	}
+	return bits, nil
+}
+
+var durationUnits = map[string]time.Duration{
+	"d":       24 * time.Hour,
+	"day":     24 * time.Hour,
+	"days":    24 * time.Hour,
+	"h":       time.Hour,
+	"hr":      time.Hour,
+	"hrs":     time.Hour,
+	"hour":    time.Hour,
+	"hours":   time.Hour,
+	"m":       time.Minute,
+	"min":     time.Minute,
+	"mins":    time.Minute,
+	"minute":  time.Minute,
+	"minutes": time.Minute,
+	"s":       time.Second,
+	"sec":     time.Second,
+	"secs":    time.Second,
+	"second":  time.Second,
+	"seconds": time.Second,
+	"ms":      time.Millisecond,
+}
+
+func parseClockDuration(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 4 {
+		return 0, parseErrorf(s, 0, "Expected [days:]hours:minutes[:seconds]")
+	}
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second}[4-len(parts):]
+	if len(parts) == 2 {
+		units = []time.Duration{time.Hour, time.Minute}
+	}
+	var d time.Duration
+	offset := 0
+	for i, part := range parts {
+		if i == len(parts)-1 && units[i] == time.Second {
+			seconds, err := strconv.ParseFloat(part, 64)
+			if err != nil || seconds < 0 {
+				return 0, parseErrorf(s, offset, "Invalid seconds %q", part)
+			}
+			d += time.Duration(seconds * float64(time.Second))
+			break
+		}
+		n, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return 0, parseErrorf(s, offset, "Invalid number %q", part)
+		}
+		d += time.Duration(n) * units[i]
+		offset += len(part) + 1
+	}
+	return d, nil
+}
+
+// ParseDuration is the inverse of DurationFormat and DurationFormatLong. It
+// also accepts [days:]hours:minutes:seconds and Go duration strings.
+func ParseDuration(s string) (time.Duration, error) {
+	str := strings.TrimSpace(s)
+	if str == "" {
+		return 0, parseErrorf(s, 0, "Expected duration")
+	}
+	if strings.Contains(str, ":") {
+		return parseClockDuration(str)
+	}
+	if d, err := time.ParseDuration(str); err == nil {
+		return d, nil
+	}
+	var d time.Duration
+	pos := 0
+	for pos < len(str) {
+		for pos < len(str) && (str[pos] == ' ' || str[pos] == ',') {
+			pos++
+		}
+		if pos == len(str) {
+			break
+		}
+		start := pos
+		for pos < len(str) && (str[pos] >= '0' && str[pos] <= '9' || str[pos] == '.') {
+			pos++
+		}
+		if pos == start {
+			return 0, parseErrorf(s, start, "Expected number")
+		}
+		n, err := strconv.ParseFloat(str[start:pos], 64)
+		if err != nil {
+			return 0, parseErrorf(s, start, "Invalid number %q", str[start:pos])
+		}
+		for pos < len(str) && str[pos] == ' ' {
+			pos++
+		}
+		unitStart := pos
+		for pos < len(str) && (str[pos] >= 'a' && str[pos] <= 'z' || str[pos] >= 'A' && str[pos] <= 'Z') {
+			pos++
+		}
+		unit, ok := durationUnits[strings.ToLower(str[unitStart:pos])]
+		if !ok {
+			return 0, parseErrorf(s, unitStart, "Unknown duration unit %q", str[unitStart:pos])
+		}
+		d += time.Duration(n * float64(unit))
+	}
+	return d, nil
+}
+
+// ParseTimeTicks parses a TimeTicks value in hundredths of a second, given
+// either as a plain number of ticks or as a duration
+func ParseTimeTicks(s string) (int64, error) {
+	str := strings.TrimSpace(s)
+	if ticks, err := strconv.ParseInt(str, 10, 64); err == nil {
+		return ticks, nil
+	}
+	if strings.HasPrefix(str, "(") {
+		if i := strings.IndexByte(str, ')'); i > 0 {
+			ticks, err := strconv.ParseInt(str[1:i], 10, 64)
+			if err != nil {
+				return 0, parseErrorf(s, 1, "Invalid ticks %q", str[1:i])
+			}
+			return ticks, nil
+		}
+	}
+	d, err := ParseDuration(str)
+	if err != nil {
+		return 0, err
+	}
+	return int64(d / (10 * time.Millisecond)), nil
+}
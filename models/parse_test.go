@@ -0,0 +1,186 @@
+package models_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+var statusEnum = &models.Enum{
+	BaseType: types.BaseTypeEnum,
+	Values: []models.NamedNumber{
+		{Name: "up", Value: 1},
+		{Name: "down", Value: 2},
+		{Name: "testing", Value: 3},
+	},
+}
+
+var parseValueTests = []struct {
+	source string
+	typ    models.Type
+	flags  models.Format
+	raw    interface{}
+}{
+	{"TimeTicks", models.Type{Module: "SNMPv2-SMI", Name: "TimeTicks", BaseType: types.BaseTypeUnsigned32}, models.FormatAll, int64(8640000)},
+	{"TC derived from TimeTicks", models.Type{Module: "TEST-MIB", Name: "TestUptime", BaseType: types.BaseTypeUnsigned32, DerivedFrom: []string{"SNMPv2-SMI::TimeTicks"}}, models.FormatAll, int64(8640000)},
+	{"TC derived from TimeTicks in long form", models.Type{Module: "TEST-MIB", Name: "TestUptime", BaseType: types.BaseTypeUnsigned32, DerivedFrom: []string{"SNMPv2-SMI::TimeTicks"}}, models.FormatString, int64(9006100)},
+	{"TimeInterval", models.Type{Module: "SNMPv2-TC", Name: "TimeInterval", BaseType: types.BaseTypeInteger32}, models.FormatAll, int64(4500)},
+	{"IpAddress", models.Type{Module: "SNMPv2-SMI", Name: "IpAddress", BaseType: types.BaseTypeOctetString}, models.FormatAll, []byte{192, 0, 2, 1}},
+	{"InetAddress with zone", models.Type{Module: "INET-ADDRESS-MIB", Name: "InetAddress", BaseType: types.BaseTypeOctetString}, models.FormatAll, []byte{0xfe, 0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 4}},
+	{"InetAddress IPv4", models.Type{Module: "INET-ADDRESS-MIB", Name: "InetAddress", BaseType: types.BaseTypeOctetString}, models.FormatAll, []byte{192, 0, 2, 1}},
+	{"InetAddress of unknown length", models.Type{Module: "INET-ADDRESS-MIB", Name: "InetAddress", BaseType: types.BaseTypeOctetString}, models.FormatAll, []byte{0x01, 0x02, 0x03}},
+	{"InetAddressIPv4z", models.Type{Module: "INET-ADDRESS-MIB", Name: "InetAddressIPv4z", BaseType: types.BaseTypeOctetString}, models.FormatAll, []byte{192, 0, 2, 1, 0, 0, 1, 0}},
+	{"InetAddressDNS", models.Type{Module: "INET-ADDRESS-MIB", Name: "InetAddressDNS", BaseType: types.BaseTypeOctetString}, models.FormatAll, []byte("peer.net")},
+	{"Enumeration", models.Type{Module: "TEST-MIB", Name: "TestStatus", BaseType: types.BaseTypeEnum, Enum: statusEnum}, models.FormatAll, int64(2)},
+	{"Units", models.Type{Module: "TEST-MIB", Name: "TestSpeed", BaseType: types.BaseTypeUnsigned32, Units: "bits/s"}, models.FormatAll | models.FormatUnits, int64(1000000)},
+}
+
+func TestParseValue(t *testing.T) {
+	for _, test := range parseValueTests {
+		v := test.typ.FormatValue(test.raw, test.flags)
+		parsed, err := test.typ.ParseValue(v.Formatted)
+		if err != nil {
+			t.Errorf("%s: ParseValue(%q): %v", test.source, v.Formatted, err)
+		} else if !reflect.DeepEqual(parsed, test.raw) {
+			t.Errorf("%s: ParseValue(%q) = %#v, want %#v", test.source, v.Formatted, parsed, test.raw)
+		}
+	}
+}
+
+func TestParseValueDerivedFormatted(t *testing.T) {
+	typ := models.Type{Module: "TEST-MIB", Name: "TestUptime", BaseType: types.BaseTypeUnsigned32, DerivedFrom: []string{"SNMPv2-SMI::TimeTicks"}}
+	if parsed, err := typ.ParseValue("1d 0h 0m"); err != nil || parsed != int64(8640000) {
+		t.Errorf("ParseValue(\"1d 0h 0m\") = %v, %v, want 8640000", parsed, err)
+	}
+	// A type named like a textual convention in another module is not one
+	typ = models.Type{Module: "TEST-MIB", Name: "TimeTicks", BaseType: types.BaseTypeUnsigned32}
+	if _, err := typ.ParseValue("1d 0h 0m"); err == nil {
+		t.Errorf("ParseValue of TEST-MIB::TimeTicks accepted a duration")
+	}
+}
+
+var inetAddressParseTests = []struct {
+	source    string
+	addrType  models.InetAddressType
+	input     string
+	octets    []byte
+	formatted string
+}{
+	{"IPv4", models.InetAddressTypeIPv4, "192.0.2.1", []byte{192, 0, 2, 1}, "192.0.2.1"},
+	{"IPv6", models.InetAddressTypeIPv6, "2001:db8::1", []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}, "2001:db8::1"},
+	{"IPv4-mapped IPv6", models.InetAddressTypeIPv6, "::ffff:192.0.2.1", []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 192, 0, 2, 1}, "192.0.2.1"},
+	{"IPv6 with zone", models.InetAddressTypeIPv6z, "fe80::1%4", []byte{0xfe, 0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 4}, "fe80::1%4"},
+	{"Unknown with zone", models.InetAddressTypeUnknown, "fe80::1%4", []byte{0xfe, 0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 4}, "fe80::1%4"},
+	// Formatting infers the type from the length, so a DNS name is only
+	// formatted as one when paired with its InetAddressType
+	{"Unknown DNS name", models.InetAddressTypeUnknown, "peer.net", []byte("peer.net"), "112.101.101.114%778986868"},
+	{"Unknown octets", models.InetAddressTypeUnknown, "01 02 03", []byte{1, 2, 3}, "01 02 03"},
+	{"Unknown empty", models.InetAddressTypeUnknown, "", []byte{}, ""},
+	{"DNS", models.InetAddressTypeDNS, "192.0.2.1", []byte("192.0.2.1"), "192.0.2.1"},
+}
+
+var inetAddressParseErrorTests = []struct {
+	source   string
+	addrType models.InetAddressType
+	input    string
+}{
+	{"IPv6 for IPv4", models.InetAddressTypeIPv4, "2001:db8::1"},
+	{"IPv4 for IPv6", models.InetAddressTypeIPv6, "192.0.2.1"},
+	{"Zone for IPv6", models.InetAddressTypeIPv6, "fe80::1%4"},
+	{"Missing zone", models.InetAddressTypeIPv6z, "fe80::1"},
+	{"Invalid zone", models.InetAddressTypeIPv6z, "fe80::1%eth0"},
+	{"Invalid address", models.InetAddressTypeUnknown, "fe80::g%4"},
+	{"Invalid octets", models.InetAddressTypeUnknown, "01 0g"},
+}
+
+func TestParseInetAddressTyped(t *testing.T) {
+	for _, test := range inetAddressParseTests {
+		octets, err := models.ParseInetAddressTyped(test.input, test.addrType)
+		if err != nil || !reflect.DeepEqual(octets, test.octets) {
+			t.Errorf("%s: ParseInetAddressTyped(%q) = % x, %v, want % x", test.source, test.input, octets, err, test.octets)
+			continue
+		}
+		v := models.GetInetAddressTypedFormatted(octets, test.addrType, models.FormatAll)
+		if v.Err != nil || v.Formatted != test.formatted {
+			t.Errorf("%s: formatted % x as %q with error %v, want %q", test.source, octets, v.Formatted, v.Err, test.formatted)
+		}
+	}
+	for _, test := range inetAddressParseErrorTests {
+		var parseErr *models.ParseError
+		if octets, err := models.ParseInetAddressTyped(test.input, test.addrType); !errors.As(err, &parseErr) {
+			t.Errorf("%s: ParseInetAddressTyped(%q) = % x, %v, want ParseError", test.source, test.input, octets, err)
+		}
+	}
+}
+
+func TestParseInetAddress(t *testing.T) {
+	for _, octets := range [][]byte{{192, 0, 2, 1}, {0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}} {
+		formatted := models.GetInetAddressFormatted(octets, models.FormatAll).Formatted
+		if parsed, err := models.ParseInetAddress(formatted, false); err != nil || !reflect.DeepEqual(parsed, octets) {
+			t.Errorf("ParseInetAddress(%q) = % x, %v, want % x", formatted, parsed, err, octets)
+		}
+	}
+	if _, err := models.ParseInetAddress("2001:db8::1", true); err == nil {
+		t.Errorf("ParseInetAddress accepted IPv6 for IPv4 only")
+	}
+}
+
+func TestParseEnum(t *testing.T) {
+	for _, value := range statusEnum.Values {
+		for _, flags := range []models.Format{models.FormatEnumName, models.FormatEnumValue, models.FormatEnumName | models.FormatEnumValue} {
+			formatted := models.GetEnumFormatted(value.Value, flags, statusEnum).Formatted
+			if parsed, err := models.ParseEnum(formatted, statusEnum); err != nil || parsed != value.Value {
+				t.Errorf("ParseEnum(%q) = %d, %v, want %d", formatted, parsed, err, value.Value)
+			}
+		}
+	}
+	for _, input := range []string{"unknown", "up down", "up(2)"} {
+		if parsed, err := models.ParseEnum(input, statusEnum); err == nil {
+			t.Errorf("ParseEnum(%q) = %d, want error", input, parsed)
+		}
+	}
+}
+
+var durationTests = []time.Duration{
+	0,
+	45 * time.Second,
+	90 * time.Minute,
+	26 * time.Hour,
+	3*24*time.Hour + 4*time.Minute,
+}
+
+func TestParseDuration(t *testing.T) {
+	for _, d := range durationTests {
+		for _, formatted := range []string{models.DurationFormat(d), models.DurationFormatLong(d)} {
+			if parsed, err := models.ParseDuration(formatted); err != nil || parsed != d {
+				t.Errorf("ParseDuration(%q) = %s, %v, want %s", formatted, parsed, err, d)
+			}
+		}
+	}
+	for _, input := range []string{"", "1 fortnight", "d", "1:2:3:4:5"} {
+		if parsed, err := models.ParseDuration(input); err == nil {
+			t.Errorf("ParseDuration(%q) = %s, want error", input, parsed)
+		}
+	}
+}
+
+func TestParseTimeTicks(t *testing.T) {
+	for _, d := range durationTests {
+		ticks := int64(d / (10 * time.Millisecond))
+		for _, flags := range []models.Format{models.FormatAll, models.FormatString} {
+			formatted := models.GetDurationFormatted(ticks, flags).Formatted
+			if parsed, err := models.ParseTimeTicks(formatted); err != nil || parsed != ticks {
+				t.Errorf("ParseTimeTicks(%q) = %d, %v, want %d", formatted, parsed, err, ticks)
+			}
+		}
+	}
+	for input, ticks := range map[string]int64{"12345": 12345, "(12345) 0:02:03.45": 12345} {
+		if parsed, err := models.ParseTimeTicks(input); err != nil || parsed != ticks {
+			t.Errorf("ParseTimeTicks(%q) = %d, %v, want %d", input, parsed, err, ticks)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"strings"
+	"sync"
+)
+
+// ValueParser parses a formatted value back to a raw value
+type ValueParser func(s string) (interface{}, error)
+
+// FormatterFunc returns the formatter for values of the type with the given
+// format flags
+type FormatterFunc func(t Type, flags Format) ValueFormatter
+
+type registeredFormatter struct {
+	formatter FormatterFunc
+	parser    ValueParser
+}
+
+var formatterRegistry = struct {
+	sync.RWMutex
+	formatters map[string]registeredFormatter
+}{
+	formatters: make(map[string]registeredFormatter),
+}
+
+// RegisterFormatter registers the formatter, and optionally the parser, for
+// values of the textual convention with the given module-qualified identity,
+// such as "IF-MIB::OwnerString", and of all types derived from it. It replaces
+// any previous registration for the identity. Registered formatters take
+// precedence over the built-in formatters.
+func RegisterFormatter(identity string, formatter ValueFormatter, parser ValueParser) {
+	RegisterFormatterFunc(identity, func(t Type, flags Format) ValueFormatter {
+		return func(value interface{}) Value {
+			v := formatter(value)
+			if v.Format == FormatNone {
+				v.Format = flags
+			}
+			return v
+		}
+	}, parser)
+}
+
+// RegisterFormatterFunc is like RegisterFormatter, but the formatter is
+// created for the type and format flags that values are formatted with
+func RegisterFormatterFunc(identity string, formatter FormatterFunc, parser ValueParser) {
+	formatterRegistry.Lock()
+	defer formatterRegistry.Unlock()
+	formatterRegistry.formatters[identity] = registeredFormatter{formatter: formatter, parser: parser}
+}
+
+// UnregisterFormatter removes the registration for the identity
+func UnregisterFormatter(identity string) {
+	formatterRegistry.Lock()
+	defer formatterRegistry.Unlock()
+	delete(formatterRegistry.formatters, identity)
+}
+
+// identities returns the module-qualified identities of the type and the
+// types it is derived from, nearest first
+func (t Type) identities() []string {
+	if t.Name == "" {
+		return t.DerivedFrom
+	}
+	return append([]string{t.Identity()}, t.DerivedFrom...)
+}
+
+// DerivesFrom reports whether the type is, or is derived from, the type with
+// the given identity, such as "SNMPv2-TC::DisplayString". An identity without
+// a module matches the type name in any module.
+func (t Type) DerivesFrom(identity string) bool {
+	qualified := strings.Contains(identity, "::")
+	for _, i := range t.identities() {
+		if i == identity || (!qualified && strings.HasSuffix(i, "::"+identity)) {
+			return true
+		}
+	}
+	return false
+}
+
+func lookupRegisteredFormatter(identities []string) (registeredFormatter, bool) {
+	formatterRegistry.RLock()
+	defer formatterRegistry.RUnlock()
+	for _, identity := range identities {
+		if f, ok := formatterRegistry.formatters[identity]; ok {
+			return f, true
+		}
+	}
+	return registeredFormatter{}, false
+}
+
+// getTCFormatter returns the formatter for the first type in the derivation
+// chain that has a registered formatter or, failing that, a built-in one
+func (t Type) getTCFormatter(flags Format) ValueFormatter {
+	identities := t.identities()
+	if f, ok := lookupRegisteredFormatter(identities); ok && f.formatter != nil {
+		return f.formatter(t, flags)
+	}
+	for _, identity := range identities {
+		formatters := tcFormatters
+		if !strings.Contains(identity, "::") {
+			formatters = legacyFormatters
+		}
+		if f, ok := formatters[identity]; ok {
+			return f(t, flags)
+		}
+	}
+	return nil
+}
+
+// getRegisteredParser returns the parser for the first type in the
+// derivation chain that has a registered parser
+func (t Type) getRegisteredParser() ValueParser {
+	if f, ok := lookupRegisteredFormatter(t.identities()); ok {
+		return f.parser
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"sync"
 
 	"github.com/sleepinggenius2/gosmi/types"
@@ -31,17 +32,28 @@ func (e *Enum) initValueMap() {
 }
 
 func (e *Enum) Name(value int64) string {
-	e.initValueMap()
-	e.rw.RLock()
-	name, ok := e.valueMap[value]
-	e.rw.RUnlock()
+	name, ok := e.lookup(value)
 	if !ok {
 		return "unknown"
 	}
 	return name
 }
 
+func (e *Enum) lookup(value int64) (string, bool) {
+	if e == nil {
+		return "", false
+	}
+	e.initValueMap()
+	e.rw.RLock()
+	defer e.rw.RUnlock()
+	name, ok := e.valueMap[value]
+	return name, ok
+}
+
 func (e *Enum) Value(name string) (int64, error) {
+	if e == nil {
+		return 0, fmt.Errorf("Unknown enum name %q", name)
+	}
 	e.initValueMap()
 	e.rw.RLock()
 	defer e.rw.RUnlock()
@@ -58,18 +70,48 @@ type NamedNumber struct {
 	Value int64
 }
 
+// Range is a range of values. The bounds of a BaseTypeUnsigned64 range may
+// exceed math.MaxInt64, so they are held in MinUnsigned and MaxUnsigned, with
+// MinValue and MaxValue clamped to math.MaxInt64.
 type Range struct {
-	BaseType types.BaseType
-	MinValue int64
-	MaxValue int64
+	BaseType    types.BaseType
+	MinValue    int64
+	MaxValue    int64
+	MinUnsigned uint64
+	MaxUnsigned uint64
+}
+
+// Contains reports whether the integer value lies within the range
+func (r Range) Contains(value interface{}) bool {
+	if r.BaseType == types.BaseTypeUnsigned64 {
+		uintVal, err := ToUint64(value)
+		return err == nil && uintVal >= r.MinUnsigned && uintVal <= r.MaxUnsigned
+	}
+	intVal, err := ToInt64(value)
+	return err == nil && intVal >= r.MinValue && intVal <= r.MaxValue
+}
+
+func (r Range) String() string {
+	if r.BaseType == types.BaseTypeUnsigned64 {
+		if r.MinUnsigned == r.MaxUnsigned {
+			return strconv.FormatUint(r.MinUnsigned, 10)
+		}
+		return fmt.Sprintf("%d..%d", r.MinUnsigned, r.MaxUnsigned)
+	}
+	if r.MinValue == r.MaxValue {
+		return strconv.FormatInt(r.MinValue, 10)
+	}
+	return fmt.Sprintf("%d..%d", r.MinValue, r.MaxValue)
 }
 
 type Type struct {
 	BaseType    types.BaseType
 	Decl        types.Decl
+	DerivedFrom []string // Module-qualified identities of the named types this type is derived from, nearest first
 	Description string
 	Enum        *Enum
 	Format      string
+	Module      string
 	Name        string
 	Ranges      []Range
 	Reference   string
@@ -113,6 +155,17 @@ func (t Type) indexValueInteger(value interface{}) (types.Oid, error) {
 	return types.Oid{types.SmiSubId(intVal)}, nil
 }
 
+func (t Type) indexValueUnsigned(value interface{}) (types.Oid, error) {
+	uintVal, err := ToUint64(value)
+	if err != nil {
+		return nil, err
+	}
+	if uintVal > 0xffffffff {
+		return nil, errors.New("Integer value outside of range")
+	}
+	return types.Oid{types.SmiSubId(uintVal)}, nil
+}
+
 func (t Type) indexValueObjectIdentifier(value types.Oid, implied bool) (types.Oid, error) {
 	var offset int
 	if !implied {
@@ -138,7 +191,12 @@ func (t Type) indexValueOctetString(value interface{}, implied bool) (types.Oid,
 	}
 	var ret types.Oid
 	var offset int
-	if implied {
+	if fixedLen, ok := t.fixedLength(); ok {
+		if len(bytes) != fixedLen {
+			return nil, fmt.Errorf("Octet string value must be %d octets, got %d", fixedLen, len(bytes))
+		}
+		ret = make(types.Oid, len(bytes))
+	} else if implied {
 		ret = make(types.Oid, len(bytes))
 	} else {
 		ret = make(types.Oid, len(bytes)+1)
@@ -155,8 +213,10 @@ func (t Type) IndexValue(value interface{}, implied bool) (types.Oid, error) {
 	switch t.BaseType {
 	case types.BaseTypeEnum:
 		return t.indexValueEnum(value)
-	case types.BaseTypeInteger32, types.BaseTypeUnsigned32:
+	case types.BaseTypeInteger32, types.BaseTypeUnsigned32, types.BaseTypeInteger64:
 		return t.indexValueInteger(value)
+	case types.BaseTypeUnsigned64:
+		return t.indexValueUnsigned(value)
 	case types.BaseTypeObjectIdentifier:
 		switch v := value.(type) {
 		case []uint32:
@@ -182,3 +242,103 @@ func (t Type) IndexValue(value interface{}, implied bool) (types.Oid, error) {
 	}
 	return nil, fmt.Errorf("Invalid base type: %v", t.BaseType)
 }
+
+func (t Type) fixedLength() (int, bool) {
+	if t.BaseType != types.BaseTypeOctetString || len(t.Ranges) != 1 || t.Ranges[0].MinValue != t.Ranges[0].MaxValue {
+		return 0, false
+	}
+	return int(t.Ranges[0].MinValue), true
+}
+
+func (t Type) decodeIndexInteger(oid types.Oid) (interface{}, int, error) {
+	if len(oid) == 0 {
+		return nil, 0, errors.New("Missing integer value")
+	}
+	return int64(oid[0]), 1, nil
+}
+
+func (t Type) decodeIndexObjectIdentifier(oid types.Oid, implied bool) (interface{}, int, error) {
+	if implied {
+		value := make(types.Oid, len(oid))
+		copy(value, oid)
+		return value, len(oid), nil
+	}
+	if len(oid) == 0 {
+		return nil, 0, errors.New("Missing object identifier length")
+	}
+	length := int(oid[0])
+	if length > len(oid)-1 {
+		return nil, 0, fmt.Errorf("Object identifier length %d exceeds remaining %d sub-identifiers", length, len(oid)-1)
+	}
+	value := make(types.Oid, length)
+	copy(value, oid[1:length+1])
+	return value, length + 1, nil
+}
+
+func (t Type) decodeIndexOctetString(oid types.Oid, implied bool) (interface{}, int, error) {
+	var length, offset int
+	if fixedLen, ok := t.fixedLength(); ok {
+		length = fixedLen
+	} else if implied {
+		length = len(oid)
+	} else {
+		if len(oid) == 0 {
+			return nil, 0, errors.New("Missing octet string length")
+		}
+		length = int(oid[0])
+		offset = 1
+	}
+	if length > len(oid)-offset {
+		return nil, 0, fmt.Errorf("Octet string length %d exceeds remaining %d sub-identifiers", length, len(oid)-offset)
+	}
+	bytes := make([]byte, length)
+	for i, subId := range oid[offset : offset+length] {
+		if subId > 0xff {
+			return nil, 0, fmt.Errorf("Sub-identifier %d is not a valid octet", subId)
+		}
+		bytes[i] = byte(subId)
+	}
+	return bytes, offset + length, nil
+}
+
+// DecodeIndex is the inverse of IndexValue. It decodes a single index value
+// from the start of oid and returns it along with the number of
+// sub-identifiers consumed.
+func (t Type) DecodeIndex(oid types.Oid, implied bool) (value interface{}, n int, err error) {
+	switch t.BaseType {
+	case types.BaseTypeEnum, types.BaseTypeInteger32, types.BaseTypeUnsigned32, types.BaseTypeInteger64:
+		return t.decodeIndexInteger(oid)
+	case types.BaseTypeUnsigned64:
+		value, n, err := t.decodeIndexInteger(oid)
+		if err != nil {
+			return nil, 0, err
+		}
+		return uint64(value.(int64)), n, nil
+	case types.BaseTypeObjectIdentifier:
+		return t.decodeIndexObjectIdentifier(oid, implied)
+	case types.BaseTypeOctetString:
+		return t.decodeIndexOctetString(oid, implied)
+	}
+	return nil, 0, fmt.Errorf("Invalid base type: %v", t.BaseType)
+}
+
+// DecodeInstance decodes the instance of a conceptual row into the values of
+// the index objects with the given types, the last of which is IMPLIED if
+// implied is set. The lengths are the number of sub-identifiers encoding each
+// value.
+func DecodeInstance(index []Type, instance types.Oid, implied bool) (values []interface{}, lengths []int, err error) {
+	values = make([]interface{}, len(index))
+	lengths = make([]int, len(index))
+	suffix := instance
+	for i, t := range index {
+		values[i], lengths[i], err = t.DecodeIndex(suffix, implied && i == len(index)-1)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Decode index %d of instance %s: %w", i+1, instance, err)
+		}
+		suffix = suffix[lengths[i]:]
+	}
+	if len(suffix) > 0 {
+		return nil, nil, fmt.Errorf("Instance %s has %d trailing sub-identifiers", instance, len(suffix))
+	}
+	return
+}
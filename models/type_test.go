@@ -0,0 +1,103 @@
+package models_test
+
+import (
+	"errors"
+
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+func TestUnsigned64Index(t *testing.T) {
+	typ := models.Type{BaseType: types.BaseTypeUnsigned64}
+	oid, err := typ.IndexValue(uint64(4294967295), false)
+	if err != nil || oid.String() != "4294967295" {
+		t.Errorf("IndexValue = %s, %v, want 4294967295", oid, err)
+	}
+	if oid, err := typ.IndexValue(uint64(4294967296), false); err == nil {
+		t.Errorf("IndexValue(4294967296) = %s, want error", oid)
+	}
+	value, n, err := typ.DecodeIndex(types.Oid{7, 1}, false)
+	if err != nil || value != uint64(7) || n != 1 {
+		t.Errorf("DecodeIndex = %#v, %d, %v, want uint64(7), 1", value, n, err)
+	}
+}
+
+func TestDecodeInstance(t *testing.T) {
+	index := []models.Type{
+		{BaseType: types.BaseTypeInteger32},
+		{BaseType: types.BaseTypeOctetString},
+		{BaseType: types.BaseTypeObjectIdentifier},
+	}
+	values, lengths, err := models.DecodeInstance(index, types.Oid{3, 2, 'a', 'b', 1, 3, 6}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values[0] != int64(3) || string(values[1].([]byte)) != "ab" || values[2].(types.Oid).String() != "1.3.6" {
+		t.Errorf("DecodeInstance = %#v", values)
+	}
+	if lengths[0] != 1 || lengths[1] != 3 || lengths[2] != 3 {
+		t.Errorf("DecodeInstance lengths = %v, want [1 3 3]", lengths)
+	}
+	for _, instance := range []types.Oid{{3, 2, 'a'}, {3, 0, 1, 3}} {
+		if _, _, err := models.DecodeInstance(index[:2], instance, false); err == nil {
+			t.Errorf("DecodeInstance(%s) gave no error", instance)
+		}
+	}
+	if !models.IsPrintable([]byte("eth0 ~")) || models.IsPrintable([]byte{'a', 0x7f}) {
+		t.Error("IsPrintable accepts only printable ASCII")
+	}
+}
+
+func TestTypeValidate(t *testing.T) {
+	status := &models.Enum{BaseType: types.BaseTypeEnum, Values: []models.NamedNumber{{Name: "up", Value: 1}, {Name: "down", Value: 2}}}
+	flags := &models.Enum{BaseType: types.BaseTypeBits, Values: []models.NamedNumber{{Name: "alpha", Value: 0}, {Name: "delta", Value: 9}}}
+	temperature := models.Type{BaseType: types.BaseTypeInteger32, Ranges: []models.Range{{BaseType: types.BaseTypeInteger32, MinValue: -10, MaxValue: 0}, {BaseType: types.BaseTypeInteger32, MinValue: 5, MaxValue: 5}}}
+	name := models.Type{BaseType: types.BaseTypeOctetString, Format: "255a", Ranges: []models.Range{{BaseType: types.BaseTypeOctetString, MinValue: 1, MaxValue: 4}}}
+	tests := []struct {
+		source string
+		typ    models.Type
+		value  interface{}
+		err    error
+	}{
+		{"In range", temperature, -10, nil},
+		{"In second range", temperature, int64(5), nil},
+		{"Between ranges", temperature, 3, models.ErrOutOfRange},
+		{"Above range", temperature, uint32(6), models.ErrOutOfRange},
+		{"Not an integer", temperature, "five", models.ErrInvalidType},
+		{"Integer32 overflow", models.Type{BaseType: types.BaseTypeInteger32}, int64(1) << 31, models.ErrOutOfRange},
+		{"Unsigned32 negative", models.Type{BaseType: types.BaseTypeUnsigned32}, -1, models.ErrOutOfRange},
+		{"Size", name, "eth0", nil},
+		{"Size of bytes", name, []byte{'a'}, nil},
+		{"Too short", name, "", models.ErrInvalidSize},
+		{"Too long", name, []int{'e', 't', 'h', '1', '0'}, models.ErrInvalidSize},
+		{"Charset", name, "a\xe9", models.ErrInvalidCharset},
+		{"Bare CR", name, "a\rb", models.ErrInvalidCharset},
+		{"CR LF", name, "a\r\n", nil},
+		{"Not an octet string", name, 7, models.ErrInvalidType},
+		{"Enum", models.Type{BaseType: types.BaseTypeEnum, Enum: status}, 2, nil},
+		{"Enum name", models.Type{BaseType: types.BaseTypeEnum, Enum: status}, "down", nil},
+		{"Enum numeric string", models.Type{BaseType: types.BaseTypeEnum, Enum: status}, "1", nil},
+		{"Unknown enum", models.Type{BaseType: types.BaseTypeEnum, Enum: status}, 3, models.ErrUnknownEnum},
+		{"Unknown enum name", models.Type{BaseType: types.BaseTypeEnum, Enum: status}, "testing", models.ErrUnknownEnum},
+		{"Bits", models.Type{BaseType: types.BaseTypeBits, Enum: flags}, []byte{0x80, 0x40}, nil},
+		{"Unknown bit", models.Type{BaseType: types.BaseTypeBits, Enum: flags}, []byte{0x40}, models.ErrUnknownBit},
+		{"Not bits", models.Type{BaseType: types.BaseTypeBits, Enum: flags}, 1, models.ErrInvalidType},
+		{"OID", models.Type{BaseType: types.BaseTypeObjectIdentifier}, "1.3.6.1", nil},
+		{"OID too long", models.Type{BaseType: types.BaseTypeObjectIdentifier}, make(types.Oid, models.MaxOidLen+1), models.ErrInvalidSize},
+		{"Not an OID", models.Type{BaseType: types.BaseTypeObjectIdentifier}, 1.5, models.ErrInvalidType},
+	}
+	for _, test := range tests {
+		err := test.typ.Validate(test.value)
+		if test.err == nil {
+			if err != nil {
+				t.Errorf("%s: Validate(%v): %v", test.source, test.value, err)
+			}
+			continue
+		}
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: Validate(%v) = %v, want %v", test.source, test.value, err, test.err)
+		}
+	}
+}
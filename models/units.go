@@ -0,0 +1,171 @@
+package models
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// UnitPrefixes selects the prefixes used to scale a quantity
+type UnitPrefixes byte
+
+const (
+	UnitPrefixesDefault UnitPrefixes = iota // IEC for octets, SI for everything else
+	UnitPrefixesSI                          // Powers of 1000: k, M, G, ...
+	UnitPrefixesIEC                         // Powers of 1024: Ki, Mi, Gi, ...
+)
+
+// Quantity is a numeric value with its unit
+type Quantity struct {
+	Value float64
+	Unit  string
+}
+
+func (q Quantity) String() string {
+	formatted := formatQuantityValue(q.Value)
+	if q.Unit == "" {
+		return formatted
+	}
+	return formatted + " " + q.Unit
+}
+
+type scalableUnit struct {
+	unit     string  // Symbol of the base unit
+	factor   float64 // Size of the unit in base units
+	prefixes UnitPrefixes
+}
+
+const unitSeconds = "s"
+
+// scalableUnits maps the commonly used UNITS clauses to their base units
+var scalableUnits = map[string]scalableUnit{
+	"octets":                 {"B", 1, UnitPrefixesIEC},
+	"bytes":                  {"B", 1, UnitPrefixesIEC},
+	"bits":                   {"bit", 1, UnitPrefixesSI},
+	"kilobits":               {"bit", 1e3, UnitPrefixesSI},
+	"bits per second":        {"bit/s", 1, UnitPrefixesSI},
+	"bits/second":            {"bit/s", 1, UnitPrefixesSI},
+	"bps":                    {"bit/s", 1, UnitPrefixesSI},
+	"kilobits per second":    {"bit/s", 1e3, UnitPrefixesSI},
+	"kbps":                   {"bit/s", 1e3, UnitPrefixesSI},
+	"megabits per second":    {"bit/s", 1e6, UnitPrefixesSI},
+	"mbits per second":       {"bit/s", 1e6, UnitPrefixesSI},
+	"mbps":                   {"bit/s", 1e6, UnitPrefixesSI},
+	"hundredths of a second": {unitSeconds, 0.01, UnitPrefixesSI},
+	"hundredths of seconds":  {unitSeconds, 0.01, UnitPrefixesSI},
+	"centi-seconds":          {unitSeconds, 0.01, UnitPrefixesSI},
+	"centiseconds":           {unitSeconds, 0.01, UnitPrefixesSI},
+	"milliseconds":           {unitSeconds, 1e-3, UnitPrefixesSI},
+	"seconds":                {unitSeconds, 1, UnitPrefixesSI},
+}
+
+var (
+	siPrefixes  = []string{"", "k", "M", "G", "T", "P", "E"}
+	iecPrefixes = []string{"", "Ki", "Mi", "Gi", "Ti", "Pi", "Ei"}
+	timeUnits   = []struct {
+		unit    string
+		seconds float64
+	}{
+		{"d", 86400},
+		{"h", 3600},
+		{"min", 60},
+		{unitSeconds, 1},
+		{"ms", 1e-3},
+	}
+)
+
+// Scale converts the quantity to a human-readable form, such as 1.5 MiB for
+// 1572864 octets or 2.5 min for 15000 hundredths of a second. Quantities with
+// an unknown unit are returned unchanged.
+func (q Quantity) Scale(prefixes UnitPrefixes) Quantity {
+	u, ok := scalableUnits[strings.ToLower(strings.TrimSpace(q.Unit))]
+	if !ok {
+		return q
+	}
+	value := q.Value * u.factor
+	if u.unit == unitSeconds {
+		for _, t := range timeUnits {
+			if math.Abs(value) >= t.seconds {
+				return Quantity{Value: value / t.seconds, Unit: t.unit}
+			}
+		}
+		return Quantity{Value: value, Unit: unitSeconds}
+	}
+	if prefixes == UnitPrefixesDefault {
+		prefixes = u.prefixes
+	}
+	base, names := 1000.0, siPrefixes
+	if prefixes == UnitPrefixesIEC {
+		base, names = 1024, iecPrefixes
+	}
+	i := 0
+	for ; i < len(names)-1 && math.Abs(value) >= base; i++ {
+		value /= base
+	}
+	return Quantity{Value: value, Unit: names[i] + u.unit}
+}
+
+func formatQuantityValue(value float64) string {
+	if value == math.Trunc(value) && math.Abs(value) < 1e15 {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	formatted := strconv.FormatFloat(value, 'f', 2, 64)
+	formatted = strings.TrimRight(formatted, "0")
+	return strings.TrimSuffix(formatted, ".")
+}
+
+func (n Node) Quantity(value interface{}) (Quantity, error) {
+	return n.Type.Quantity(value)
+}
+
+func (n ScalarNode) Quantity(value interface{}) (Quantity, error) {
+	return n.Type.Quantity(value)
+}
+
+func (n ColumnNode) Quantity(value interface{}) (Quantity, error) {
+	return n.Type.Quantity(value)
+}
+
+// Quantity returns the numeric value with the UNITS of the type, taking the
+// implied decimal places of a d-N DISPLAY-HINT into account
+func (t Type) Quantity(value interface{}) (q Quantity, err error) {
+	q.Unit = t.Units
+	if t.BaseType == types.BaseTypeUnsigned64 {
+		var uintVal uint64
+		if uintVal, err = ToUint64(value); err != nil {
+			return
+		}
+		q.Value = float64(uintVal)
+	} else {
+		var intVal int64
+		if intVal, err = ToInt64(value); err != nil {
+			return
+		}
+		q.Value = float64(intVal)
+	}
+	if hint, hintErr := CompileIntegerHint(t.Format); hintErr == nil && hint.Decimals > 0 {
+		q.Value /= math.Pow10(hint.Decimals)
+	}
+	return
+}
+
+// formatUnits sets the units of the value and, with FormatUnits or
+// FormatUnitsScaled, renders them
+func (t Type) formatUnits(v Value, flags Format) Value {
+	v.Units = t.Units
+	if flags&(FormatUnits|FormatUnitsScaled) == 0 || v.Formatted == "" {
+		return v
+	}
+	if flags&FormatUnitsScaled != 0 {
+		if q, err := t.Quantity(v.Raw); err == nil {
+			if scaled := q.Scale(UnitPrefixesDefault); scaled.Unit != q.Unit {
+				v.Formatted = scaled.String()
+				return v
+			}
+		}
+	}
+	v.Formatted += " " + t.Units
+	return v
+}
@@ -0,0 +1,51 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+var scaleTests = []struct {
+	source   string
+	quantity models.Quantity
+	prefixes models.UnitPrefixes
+	scaled   string
+}{
+	{"Octets", models.Quantity{Value: 1572864, Unit: "octets"}, models.UnitPrefixesDefault, "1.5 MiB"},
+	{"Octets with SI prefixes", models.Quantity{Value: 1500000, Unit: "Octets"}, models.UnitPrefixesSI, "1.5 MB"},
+	{"Octets below a kibibyte", models.Quantity{Value: 1000, Unit: "octets"}, models.UnitPrefixesDefault, "1000 B"},
+	{"Bits per second", models.Quantity{Value: 10000000000, Unit: "bits per second"}, models.UnitPrefixesDefault, "10 Gbit/s"},
+	{"Kilobits per second", models.Quantity{Value: 1500, Unit: "kbps"}, models.UnitPrefixesDefault, "1.5 Mbit/s"},
+	{"Minutes", models.Quantity{Value: 15000, Unit: "hundredths of a second"}, models.UnitPrefixesDefault, "2.5 min"},
+	{"Hours", models.Quantity{Value: 540000, Unit: "hundredths of a second"}, models.UnitPrefixesDefault, "1.5 h"},
+	{"Days", models.Quantity{Value: 8640000, Unit: "centiseconds"}, models.UnitPrefixesDefault, "1 d"},
+	{"Milliseconds", models.Quantity{Value: 5, Unit: "hundredths of a second"}, models.UnitPrefixesDefault, "50 ms"},
+	{"Unknown unit", models.Quantity{Value: 1234567, Unit: "packets"}, models.UnitPrefixesDefault, "1234567 packets"},
+}
+
+func TestQuantityScale(t *testing.T) {
+	for _, test := range scaleTests {
+		if scaled := test.quantity.Scale(test.prefixes).String(); scaled != test.scaled {
+			t.Errorf("%s: Scale(%s) = %q, want %q", test.source, test.quantity, scaled, test.scaled)
+		}
+	}
+	unknown := models.Quantity{Value: 5, Unit: "widgets"}
+	if scaled := unknown.Scale(models.UnitPrefixesSI); scaled != unknown {
+		t.Errorf("Unknown unit: Scale = %#v, want it unchanged", scaled)
+	}
+}
+
+func TestFormatUnitsWithDecimals(t *testing.T) {
+	typ := models.Type{BaseType: types.BaseTypeInteger32, Format: "d-2", Units: "seconds"}
+	if v := typ.FormatValue(int64(15025), models.FormatUnits); v.Formatted != "150.25 seconds" || v.Units != "seconds" {
+		t.Errorf("FormatUnits = %q with units %q, want \"150.25 seconds\"", v.Formatted, v.Units)
+	}
+	if v := typ.FormatValue(int64(15025), models.FormatUnitsScaled); v.Formatted != "2.5 min" {
+		t.Errorf("FormatUnitsScaled = %q, want \"2.5 min\"", v.Formatted)
+	}
+	if v := typ.FormatValue(int64(15025), models.FormatNone); v.Formatted != "" || v.Units != "seconds" {
+		t.Errorf("FormatNone = %q with units %q, want no formatting", v.Formatted, v.Units)
+	}
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+var (
+	ErrInvalidType    = errors.New("Invalid value type")
+	ErrOutOfRange     = errors.New("Value out of range")
+	ErrInvalidSize    = errors.New("Invalid size")
+	ErrUnknownEnum    = errors.New("Unknown enumeration value")
+	ErrUnknownBit     = errors.New("Unknown bit")
+	ErrInvalidCharset = errors.New("Invalid character")
+)
+
+// Maximum number of sub-identifiers in an OBJECT IDENTIFIER value
+const MaxOidLen = 128
+
+func (n Node) Validate(value interface{}) error {
+	return n.Type.Validate(value)
+}
+
+func (n ScalarNode) Validate(value interface{}) error {
+	return n.Type.Validate(value)
+}
+
+func (n ColumnNode) Validate(value interface{}) error {
+	return n.Type.Validate(value)
+}
+
+func formatRanges(ranges []Range) string {
+	s := make([]string, len(ranges))
+	for i, r := range ranges {
+		s[i] = r.String()
+	}
+	return strings.Join(s, " | ")
+}
+
+// Validate checks the value against the constraints of the type: ranges,
+// SIZE, enumerated values, named bits and the character set of the
+// DISPLAY-HINT. The value may be any raw value accepted by FormatValue. The
+// returned error wraps one of the Err values, so that it can be mapped to an
+// SNMP error status.
+func (t Type) Validate(value interface{}) error {
+	switch t.BaseType {
+	case types.BaseTypeOctetString:
+		return t.validateOctetString(value)
+	case types.BaseTypeBits:
+		return t.validateBits(value)
+	case types.BaseTypeEnum:
+		return t.validateEnum(value)
+	case types.BaseTypeObjectIdentifier:
+		return t.validateObjectIdentifier(value)
+	case types.BaseTypeInteger32, types.BaseTypeInteger64, types.BaseTypeUnsigned32, types.BaseTypeUnsigned64:
+		return t.validateInteger(value)
+	}
+	return nil
+}
+
+func (t Type) validateInteger(value interface{}) error {
+	ranges := t.Ranges
+	if len(ranges) == 0 {
+		switch t.BaseType {
+		case types.BaseTypeInteger32:
+			ranges = []Range{{BaseType: t.BaseType, MinValue: math.MinInt32, MaxValue: math.MaxInt32}}
+		case types.BaseTypeUnsigned32:
+			ranges = []Range{{BaseType: t.BaseType, MinValue: 0, MaxValue: math.MaxUint32}}
+		case types.BaseTypeUnsigned64:
+			ranges = []Range{{BaseType: t.BaseType, MinValue: 0, MaxValue: math.MaxInt64, MinUnsigned: 0, MaxUnsigned: math.MaxUint64}}
+		default:
+			ranges = []Range{{BaseType: t.BaseType, MinValue: math.MinInt64, MaxValue: math.MaxInt64}}
+		}
+	}
+	var err error
+	if t.BaseType == types.BaseTypeUnsigned64 {
+		_, err = ToUint64(value)
+	} else {
+		_, err = ToInt64(value)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidType, err)
+	}
+	for _, r := range ranges {
+		if r.Contains(value) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %v is not in %s", ErrOutOfRange, value, formatRanges(ranges))
+}
+
+func (t Type) validateEnum(value interface{}) error {
+	if t.Enum == nil {
+		return t.validateInteger(value)
+	}
+	if name, ok := value.(string); ok {
+		if _, err := strconv.ParseInt(name, 10, 64); err != nil {
+			if _, err := t.Enum.Value(name); err != nil {
+				return fmt.Errorf("%w: %q", ErrUnknownEnum, name)
+			}
+			return nil
+		}
+	}
+	intVal, err := ToInt64(value)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidType, err)
+	}
+	for _, namedNumber := range t.Enum.Values {
+		if namedNumber.Value == intVal {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %d", ErrUnknownEnum, intVal)
+}
+
+func (t Type) validateBits(value interface{}) error {
+	bytes, ok := getBytes(value)
+	if !ok {
+		return fmt.Errorf("%w: %T is not a BITS value", ErrInvalidType, value)
+	}
+	if t.Enum == nil {
+		return nil
+	}
+	defined := make(map[int64]bool, len(t.Enum.Values))
+	for _, namedNumber := range t.Enum.Values {
+		defined[namedNumber.Value] = true
+	}
+	for i, b := range bytes {
+		for j := 0; j < 8; j++ {
+			bit := int64(i*8 + j)
+			if b&(0x80>>uint(j)) != 0 && !defined[bit] {
+				return fmt.Errorf("%w: bit %d is set", ErrUnknownBit, bit)
+			}
+		}
+	}
+	return nil
+}
+
+func (t Type) validateOctetString(value interface{}) error {
+	bytes, ok := getBytes(value)
+	if !ok {
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%w: %T is not an OCTET STRING value", ErrInvalidType, value)
+		}
+		bytes = []byte(str)
+	}
+	if len(t.Ranges) > 0 {
+		inRange := false
+		for _, r := range t.Ranges {
+			if r.Contains(len(bytes)) {
+				inRange = true
+				break
+			}
+		}
+		if !inRange {
+			return fmt.Errorf("%w: length %d is not in SIZE (%s)", ErrInvalidSize, len(bytes), formatRanges(t.Ranges))
+		}
+	}
+	if len(t.Format) > 1 {
+		return getDisplayHint(t.Format).Validate(bytes)
+	}
+	return nil
+}
+
+func (t Type) validateObjectIdentifier(value interface{}) error {
+	oid, err := ToOid(value)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidType, err)
+	}
+	if len(oid) > MaxOidLen {
+		return fmt.Errorf("%w: %d sub-identifiers, at most %d allowed", ErrInvalidSize, len(oid), MaxOidLen)
+	}
+	return nil
+}
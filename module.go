@@ -2,6 +2,7 @@ package gosmi
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/sleepinggenius2/gosmi/models"
 	"github.com/sleepinggenius2/gosmi/smi"
@@ -107,6 +108,20 @@ func GetLoadedModules() (modules []SmiModule) {
 	return
 }
 
+// GetDefiningModules returns every loaded module that defines a node or type
+// with the given descriptor, in load order. Any module qualifier is ignored.
+func GetDefiningModules(name string) (modules []SmiModule) {
+	if i := strings.Index(name, "::"); i >= 0 {
+		name = name[i+2:]
+	}
+	for smiModule := smi.GetFirstModule(); smiModule != nil; smiModule = smi.GetNextModule(smiModule) {
+		if smi.GetNode(smiModule, name) != nil || smi.GetType(smiModule, name) != nil {
+			modules = append(modules, CreateModule(smiModule))
+		}
+	}
+	return
+}
+
 func IsLoaded(moduleName string) bool {
 	return smi.IsLoaded(moduleName)
 }
@@ -119,3 +134,29 @@ func GetModule(name string) (module SmiModule, err error) {
 	}
 	return CreateModule(smiModule), nil
 }
+
+// moduleSet tracks which loaded modules have been seen by an index
+type moduleSet map[*types.SmiModule]bool
+
+// update marks all loaded modules as seen and returns those that were not
+// seen before. If any module that was seen has since been unloaded, reset is
+// true and all loaded modules are returned.
+func (s *moduleSet) update() (added []*types.SmiModule, reset bool) {
+	seen := 0
+	for smiModule := smi.GetFirstModule(); smiModule != nil; smiModule = smi.GetNextModule(smiModule) {
+		if (*s)[smiModule] {
+			seen++
+		}
+	}
+	if *s == nil || seen < len(*s) {
+		reset = len(*s) > 0
+		*s = make(moduleSet)
+	}
+	for smiModule := smi.GetFirstModule(); smiModule != nil; smiModule = smi.GetNextModule(smiModule) {
+		if !(*s)[smiModule] {
+			(*s)[smiModule] = true
+			added = append(added, smiModule)
+		}
+	}
+	return
+}
@@ -19,6 +19,53 @@ func (n SmiNode) GetModule() (module SmiModule) {
 	return CreateModule(smiModule)
 }
 
+func (n SmiNode) GetParent() (parent SmiNode, ok bool) {
+	smiParent := smi.GetParentNode(n.smiNode)
+	if smiParent == nil {
+		return
+	}
+	return CreateNode(smiParent), true
+}
+
+func (n SmiNode) GetChildren() (children []SmiNode) {
+	for smiChild := smi.GetFirstChildNode(n.smiNode); smiChild != nil; smiChild = smi.GetNextChildNode(smiChild) {
+		children = append(children, CreateNode(smiChild))
+	}
+	return
+}
+
+// GetAncestors returns the names of the nodes on the path from the root down
+// to the parent of this node
+func (n SmiNode) GetAncestors() (names []string) {
+	for smiParent := smi.GetParentNode(n.smiNode); smiParent != nil; smiParent = smi.GetParentNode(smiParent) {
+		names = append(names, string(smiParent.Name))
+	}
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+	return
+}
+
+// GetSiblings returns the other children of the parent of this node, or the
+// other nodes directly under the root for a node such as iso
+func (n SmiNode) GetSiblings() (siblings []SmiNode) {
+	if n.smiNode == nil {
+		return
+	}
+	var smiSibling *types.SmiNode
+	if n.OidLen == 1 {
+		smiSibling = smi.GetFirstRootNode()
+	} else {
+		smiSibling = smi.GetFirstChildNode(smi.GetParentNode(n.smiNode))
+	}
+	for ; smiSibling != nil; smiSibling = smi.GetNextChildNode(smiSibling) {
+		if !smiSibling.Oid.Equals(n.Oid) {
+			siblings = append(siblings, CreateNode(smiSibling))
+		}
+	}
+	return
+}
+
 func (n SmiNode) GetSubtree() (nodes []SmiNode) {
 	first := true
 	smiNode := n.smiNode
@@ -0,0 +1,156 @@
+package gosmi_test
+
+import (
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi"
+)
+
+func nodeNames(nodes []gosmi.SmiNode) []string {
+	names := make([]string, len(nodes))
+	for i, node := range nodes {
+		names[i] = node.Name
+	}
+	return names
+}
+
+func getNode(t *testing.T, name string) gosmi.SmiNode {
+	t.Helper()
+	node, err := gosmi.GetNode(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return node
+}
+
+func TestGetParent(t *testing.T) {
+	tests := []struct {
+		node   string
+		parent string
+		module string
+	}{
+		{"ifDescr", "ifEntry", "IF-MIB"},
+		{"ifXEntry", "ifXTable", "IF-MIB"},
+		// Parents imported from other modules resolve to their definitions,
+		// rather than to the incomplete objects of the importing module
+		{"testMIB", "enterprises", "SNMPv2-SMI"},
+		{"ifMIB", "mib-2", "SNMPv2-SMI"},
+		{"linkDown", "snmpTraps", "SNMPv2-MIB"},
+		{"iso", "", ""},
+	}
+	for _, test := range tests {
+		parent, ok := getNode(t, test.node).GetParent()
+		if test.parent == "" {
+			if ok {
+				t.Errorf("%s: GetParent = %s, want none", test.node, parent.Name)
+			}
+			continue
+		}
+		if !ok || parent.Name != test.parent || parent.GetModule().Name != test.module {
+			t.Errorf("%s: GetParent = %s::%s, %t, want %s::%s", test.node, parent.GetModule().Name, parent.Name, ok, test.module, test.parent)
+		}
+	}
+}
+
+func TestGetParentImported(t *testing.T) {
+	// testAlias is a second object for the node of testObjects, so the parent
+	// of testAliasUser is found through the import of its module
+	parent, ok := getNode(t, "testAliasUser").GetParent()
+	if !ok || parent.Name != "testAlias" || parent.GetModule().Name != "TEST-ALIAS-MIB" {
+		t.Errorf("testAliasUser: GetParent = %s::%s, %t, want TEST-ALIAS-MIB::testAlias", parent.GetModule().Name, parent.Name, ok)
+	}
+	if parent, _ := getNode(t, "testEntry").GetParent(); parent.Name != "testTable" {
+		t.Errorf("testEntry: GetParent = %s, want testTable", parent.Name)
+	}
+}
+
+func TestGetChildren(t *testing.T) {
+	tests := []struct {
+		node     string
+		children []string
+	}{
+		{"ifEntry", []string{"ifIndex", "ifDescr", "ifMtu", "ifSpeed", "ifPhysAddress", "ifAdminStatus", "ifOperStatus", "ifLastChange", "ifInOctets"}},
+		{"ifXEntry", []string{"ifName", "ifHCInOctets", "ifAlias"}},
+		{"ipAddrEntry", []string{"ipAdEntAddr", "ipAdEntIfIndex", "ipAdEntNetMask"}},
+		{"ifDescr", []string{}},
+	}
+	for _, test := range tests {
+		if children := nodeNames(getNode(t, test.node).GetChildren()); !equalStrings(children, test.children) {
+			t.Errorf("%s: GetChildren = %v, want %v", test.node, children, test.children)
+		}
+	}
+}
+
+func TestGetAncestors(t *testing.T) {
+	tests := []struct {
+		node      string
+		ancestors []string
+	}{
+		{"ifDescr", []string{"iso", "org", "dod", "internet", "mgmt", "mib-2", "interfaces", "ifTable", "ifEntry"}},
+		{"testEntryValue", []string{"iso", "org", "dod", "internet", "private", "enterprises", "testMIB", "testObjects", "testTable", "testEntry"}},
+		{"iso", []string{}},
+	}
+	for _, test := range tests {
+		if ancestors := getNode(t, test.node).GetAncestors(); !equalStrings(ancestors, test.ancestors) {
+			t.Errorf("%s: GetAncestors = %v, want %v", test.node, ancestors, test.ancestors)
+		}
+	}
+}
+
+func TestGetSiblings(t *testing.T) {
+	tests := []struct {
+		node     string
+		siblings []string
+	}{
+		{"ifName", []string{"ifHCInOctets", "ifAlias"}},
+		{"ifTable", []string{"ifNumber"}},
+		// Only the nodes directly under the root, not their descendants
+		{"iso", []string{"ccitt", "joint-iso-ccitt"}},
+		{"ccitt", []string{"iso", "joint-iso-ccitt"}},
+	}
+	for _, test := range tests {
+		if siblings := nodeNames(getNode(t, test.node).GetSiblings()); !equalStrings(siblings, test.siblings) {
+			t.Errorf("%s: GetSiblings = %v, want %v", test.node, siblings, test.siblings)
+		}
+	}
+}
+
+func TestGetNodeQualified(t *testing.T) {
+	ifMIB, err := gosmi.GetModule("IF-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	snmpv2MIB, err := gosmi.GetModule("SNMPv2-MIB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		source string
+		name   string
+		module []gosmi.SmiModule
+		want   string
+	}{
+		{"Qualified", "IF-MIB::ifDescr", nil, "IF-MIB::ifDescr"},
+		{"Qualified in same module", "IF-MIB::ifDescr", []gosmi.SmiModule{ifMIB}, "IF-MIB::ifDescr"},
+		// The qualifier takes precedence over the module
+		{"Qualified in other module", "IF-MIB::ifDescr", []gosmi.SmiModule{snmpv2MIB}, "IF-MIB::ifDescr"},
+		{"Unqualified in other module", "ifDescr", []gosmi.SmiModule{snmpv2MIB}, ""},
+		{"Wrong qualifier", "SNMPv2-MIB::ifDescr", []gosmi.SmiModule{ifMIB}, ""},
+		{"Unknown qualifier", "NO-SUCH-MIB::ifDescr", nil, ""},
+	}
+	for _, test := range tests {
+		node, err := gosmi.GetNode(test.name, test.module...)
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("%s: GetNode(%s) = %s::%s, want error", test.source, test.name, node.GetModule().Name, node.Name)
+			}
+			continue
+		}
+		if err != nil || node.GetModule().Name+"::"+node.Name != test.want {
+			t.Errorf("%s: GetNode(%s) = %s::%s, %v, want %s", test.source, test.name, node.GetModule().Name, node.Name, err, test.want)
+		}
+	}
+	if modules := gosmi.GetDefiningModules("IF-MIB::ifDescr"); len(modules) != 1 || modules[0].Name != "IF-MIB" {
+		t.Errorf("GetDefiningModules(IF-MIB::ifDescr) = %v, want IF-MIB", modules)
+	}
+}
@@ -0,0 +1,417 @@
+package gosmi
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/smi"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+type OidFormat byte
+
+const OidFormatNumeric OidFormat = 0 // 1.3.6.1.2.1.2.2.1.2.3
+
+const (
+	OidFormatName      OidFormat = 1 << iota // ifDescr.3
+	OidFormatQualified                       // IF-MIB::ifDescr.3
+	OidFormatFull                            // .iso.org.dod.internet.mgmt.mib-2.interfaces.ifTable.ifEntry.ifDescr.3
+	OidFormatIndex                           // ifName."eth0"
+	OidFormatBrackets                        // ifName["eth0"]
+)
+
+type oidComponentKind int
+
+const (
+	oidComponentNumber oidComponentKind = iota
+	oidComponentName
+	oidComponentOctets
+)
+
+type oidComponent struct {
+	kind   oidComponentKind
+	number types.SmiSubId
+	name   string
+	octets []byte
+}
+
+func scanQuoted(s string) (int, error) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, nil
+		}
+	}
+	return 0, errors.New("Unterminated quoted string")
+}
+
+func scanHexString(s string) (int, error) {
+	i := strings.IndexByte(s[1:], '\'')
+	if i < 0 || i+2 >= len(s) || (s[i+2] != 'H' && s[i+2] != 'h') {
+		return 0, errors.New("Unterminated hex string")
+	}
+	return i + 3, nil
+}
+
+func scanBracket(s string) (int, error) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			n, err := scanQuoted(s[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n - 1
+		case ']':
+			return i + 1, nil
+		}
+	}
+	return 0, errors.New("Unterminated index bracket")
+}
+
+func parseOctets(s string) ([]byte, error) {
+	if s[0] == '"' {
+		str, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid quoted string %s: %w", s, err)
+		}
+		return []byte(str), nil
+	}
+	bytes, err := hex.DecodeString(s[1 : len(s)-2])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid hex string %s: %w", s, err)
+	}
+	return bytes, nil
+}
+
+func splitOid(s string) (components []oidComponent, brackets []string, err error) {
+	for len(s) > 0 {
+		var n int
+		var component oidComponent
+		switch s[0] {
+		case '[':
+			n, err = scanBracket(s)
+			if err != nil {
+				return
+			}
+			brackets = append(brackets, strings.TrimSpace(s[1:n-1]))
+			s = s[n:]
+			continue
+		case '"', '\'':
+			if s[0] == '"' {
+				n, err = scanQuoted(s)
+			} else {
+				n, err = scanHexString(s)
+			}
+			if err != nil {
+				return
+			}
+			component.kind = oidComponentOctets
+			component.octets, err = parseOctets(s[:n])
+			if err != nil {
+				return
+			}
+		default:
+			n = strings.IndexAny(s, ".[")
+			if n < 0 {
+				n = len(s)
+			}
+			part := s[:n]
+			if part == "" {
+				err = errors.New("Empty sub-identifier")
+				return
+			}
+			if part[0] >= '0' && part[0] <= '9' {
+				var subId uint64
+				subId, err = strconv.ParseUint(part, 10, 32)
+				if err != nil {
+					err = fmt.Errorf("Invalid sub-identifier %q: %w", part, err)
+					return
+				}
+				component.kind = oidComponentNumber
+				component.number = types.SmiSubId(subId)
+			} else {
+				component.kind = oidComponentName
+				component.name = part
+			}
+		}
+		if len(brackets) > 0 {
+			err = errors.New("Index brackets must come last")
+			return
+		}
+		components = append(components, component)
+		s = s[n:]
+		if len(s) > 0 && s[0] == '.' {
+			s = s[1:]
+			if len(s) == 0 {
+				err = errors.New("Trailing '.'")
+				return
+			}
+		} else if len(s) > 0 && s[0] != '[' {
+			err = fmt.Errorf("Unexpected %q", s)
+			return
+		}
+	}
+	return
+}
+
+func getExactNode(oid types.Oid) *types.SmiNode {
+	smiNode := smi.GetNodeByOID(oid)
+	if smiNode == nil || smiNode.OidLen != len(oid) {
+		return nil
+	}
+	return smiNode
+}
+
+func getChildNode(oid types.Oid, name string) *types.SmiNode {
+	var smiNode *types.SmiNode
+	if len(oid) == 0 {
+		smiNode = smi.GetNode(nil, name)
+		if smiNode == nil || smiNode.OidLen != 1 {
+			return nil
+		}
+		return smiNode
+	}
+	parent := getExactNode(oid)
+	for smiNode = smi.GetFirstChildNode(parent); smiNode != nil; smiNode = smi.GetNextChildNode(smiNode) {
+		if string(smiNode.Name) == name {
+			return smiNode
+		}
+	}
+	return nil
+}
+
+func parseIndexValue(column SmiNode, s string) (interface{}, error) {
+	if column.Type == nil {
+		return nil, fmt.Errorf("Index %s has no type", column.Name)
+	}
+	if s == "" {
+		return nil, fmt.Errorf("Empty value for index %s", column.Name)
+	}
+	switch column.Type.BaseType {
+	case types.BaseTypeEnum:
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i, nil
+		}
+		return s, nil
+	case types.BaseTypeInteger32, types.BaseTypeUnsigned32, types.BaseTypeInteger64:
+		return strconv.ParseInt(s, 10, 64)
+	case types.BaseTypeUnsigned64:
+		return strconv.ParseUint(s, 10, 64)
+	case types.BaseTypeObjectIdentifier:
+		if s[0] != '"' && s[0] != '\'' {
+			return ParseOID(s)
+		}
+	case types.BaseTypeOctetString:
+		if s[0] == '"' || s[0] == '\'' {
+			return parseOctets(s)
+		}
+		if ip := net.ParseIP(s); ip != nil {
+			if ip4 := ip.To4(); ip4 != nil {
+				return []byte(ip4), nil
+			}
+			return []byte(ip), nil
+		}
+		// An unquoted value that is not an address is taken literally, as in
+		// ifName[eth0]
+		return []byte(s), nil
+	}
+	return nil, fmt.Errorf("Invalid value %q for index %s", s, column.Name)
+}
+
+// ParseOID resolves a numeric, symbolic or mixed OID, as accepted by
+// snmptranslate, into its numeric form. Examples:
+//
+//	IF-MIB::ifDescr.3
+//	ifDescr.3
+//	.iso.org.dod.internet.mgmt.mib-2
+//	1.3.6.1.2.1.ifMIB
+//	ifName."eth0"
+//	ifDescr[3]
+//	ipAddrTable.1.1.10.0.0.1
+func ParseOID(s string) (oid types.Oid, err error) {
+	input := strings.TrimSpace(s)
+	s = input
+	if s == "" {
+		return nil, errors.New("OID is empty")
+	}
+
+	var smiModule *types.SmiModule
+	var absolute bool
+	if i := strings.Index(s, "::"); i >= 0 && !strings.ContainsAny(s[:i], "\"'[") {
+		smiModule = smi.GetModule(s[:i])
+		if smiModule == nil {
+			return nil, fmt.Errorf("Could not find module named %s", s[:i])
+		}
+		s = s[i+2:]
+	} else if s[0] == '.' {
+		absolute = true
+		s = s[1:]
+	}
+
+	components, brackets, err := splitOid(s)
+	if err != nil {
+		return nil, fmt.Errorf("Parse OID %q: %w", input, err)
+	}
+	if len(components) == 0 {
+		return nil, fmt.Errorf("Parse OID %q: No sub-identifiers", input)
+	}
+	if smiModule != nil && components[0].kind != oidComponentName {
+		return nil, fmt.Errorf("Parse OID %q: Expected descriptor after module name", input)
+	}
+
+	var smiNode *types.SmiNode
+	for i, component := range components {
+		switch component.kind {
+		case oidComponentNumber:
+			oid = append(oid, component.number)
+		case oidComponentName:
+			if i == 0 && !absolute {
+				smiNode = smi.GetNode(smiModule, component.name)
+			} else {
+				smiNode = getChildNode(oid, component.name)
+			}
+			if smiNode == nil {
+				return nil, fmt.Errorf("Parse OID %q: Could not resolve %s", input, component.name)
+			}
+			oid = append(types.Oid{}, smiNode.Oid...)
+		case oidComponentOctets:
+			implied := false
+			if i == len(components)-1 && smiNode != nil {
+				implied = CreateNode(smiNode).GetImplied()
+			}
+			if !implied {
+				oid = append(oid, types.SmiSubId(len(component.octets)))
+			}
+			for _, b := range component.octets {
+				oid = append(oid, types.SmiSubId(b))
+			}
+		}
+	}
+
+	if len(brackets) == 0 {
+		return oid, nil
+	}
+	smiNode = getExactNode(oid)
+	if smiNode == nil {
+		return nil, fmt.Errorf("Parse OID %q: Index given for unknown node", input)
+	}
+	node := CreateNode(smiNode)
+	index := node.GetIndex()
+	if len(brackets) > len(index) {
+		return nil, fmt.Errorf("Parse OID %q: Too many index values given for %s", input, node.Name)
+	}
+	implied := node.GetImplied()
+	for i, bracket := range brackets {
+		value, err := parseIndexValue(index[i], bracket)
+		if err != nil {
+			return nil, fmt.Errorf("Parse OID %q: %w", input, err)
+		}
+		indexOid, err := index[i].Type.IndexValue(value, implied && i == len(index)-1)
+		if err != nil {
+			return nil, fmt.Errorf("Parse OID %q: %s: %w", input, index[i].Name, err)
+		}
+		oid = append(oid, indexOid...)
+	}
+	return oid, nil
+}
+
+func formatIndexValue(t *models.Type, value interface{}) string {
+	switch v := value.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case types.Oid:
+		return v.String()
+	case []byte:
+		if t.Name == "IpAddress" && len(v) == net.IPv4len {
+			return net.IP(v).String()
+		}
+		if models.IsPrintable(v) {
+			return strconv.Quote(string(v))
+		}
+		return "'" + hex.EncodeToString(v) + "'H"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func renderIndex(node SmiNode, suffix types.Oid, brackets bool) (string, bool) {
+	if node.Kind != types.NodeColumn || len(suffix) == 0 {
+		return "", false
+	}
+	index := node.GetIndex()
+	indexTypes := make([]models.Type, len(index))
+	for i, column := range index {
+		if column.Type == nil {
+			return "", false
+		}
+		indexTypes[i] = *column.Type
+	}
+	values, lengths, err := models.DecodeInstance(indexTypes, suffix, node.GetImplied())
+	if err != nil {
+		return "", false
+	}
+	var b strings.Builder
+	for i, value := range values {
+		formatted := formatIndexValue(&indexTypes[i], value)
+		if brackets {
+			b.WriteString("[" + formatted + "]")
+		} else if _, ok := value.([]byte); ok {
+			b.WriteString("." + formatted)
+		} else {
+			b.WriteString("." + suffix[:lengths[i]].String())
+		}
+		suffix = suffix[lengths[i]:]
+	}
+	return b.String(), true
+}
+
+// RenderOID is the inverse of ParseOID, rendering a numeric OID in the given format
+func RenderOID(oid types.Oid, format OidFormat) string {
+	if format&(OidFormatName|OidFormatQualified|OidFormatFull) == 0 {
+		return oid.String()
+	}
+	smiNode := smi.GetNodeByOID(oid)
+	if smiNode == nil || smiNode.Name == "" {
+		return oid.String()
+	}
+
+	var b strings.Builder
+	if format&OidFormatFull != 0 {
+		for i := 1; i <= smiNode.OidLen; i++ {
+			b.WriteByte('.')
+			if n := getExactNode(oid[:i]); n != nil && n.Name != "" {
+				b.WriteString(string(n.Name))
+			} else {
+				b.WriteString(strconv.FormatUint(uint64(oid[i-1]), 10))
+			}
+		}
+	} else {
+		flags := types.RenderName
+		if format&OidFormatQualified != 0 {
+			flags |= types.RenderQualified
+		}
+		b.WriteString(smi.RenderNode(smiNode, flags))
+	}
+
+	suffix := oid[smiNode.OidLen:]
+	if format&(OidFormatIndex|OidFormatBrackets) != 0 {
+		if index, ok := renderIndex(CreateNode(smiNode), suffix, format&OidFormatBrackets != 0); ok {
+			b.WriteString(index)
+			return b.String()
+		}
+	}
+	for _, subId := range suffix {
+		b.WriteByte('.')
+		b.WriteString(strconv.FormatUint(uint64(subId), 10))
+	}
+	return b.String()
+}
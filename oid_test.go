@@ -0,0 +1,91 @@
+package gosmi_test
+
+import (
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+var parseOidTests = []struct {
+	source string
+	input  string
+	oid    string
+}{
+	{"Numeric", "1.3.6.1.2.1.2.2.1.2.3", "1.3.6.1.2.1.2.2.1.2.3"},
+	{"Qualified", "IF-MIB::ifDescr.3", "1.3.6.1.2.1.2.2.1.2.3"},
+	{"Name", "ifDescr.3", "1.3.6.1.2.1.2.2.1.2.3"},
+	{"Full", ".iso.org.dod.internet.mgmt.mib-2.interfaces", "1.3.6.1.2.1.2"},
+	{"Mixed", "1.3.6.1.2.1.ifMIB", "1.3.6.1.2.1.31"},
+	{"Quoted index", `ifName."eth0"`, "1.3.6.1.2.1.31.1.1.1.1.4.101.116.104.48"},
+	{"Bracketed integer", "ifDescr[3]", "1.3.6.1.2.1.2.2.1.2.3"},
+	{"Bracketed IpAddress", "ipAdEntIfIndex[192.0.2.1]", "1.3.6.1.2.1.4.20.1.2.192.0.2.1"},
+	{"Bracketed quoted string and implied OID", `testEntryValue["a"][1.3]`, "1.3.6.1.4.1.99999.1.10.1.3.1.97.1.3"},
+	{"Bracketed unquoted string", "testEntryValue[eth0][1.3]", "1.3.6.1.4.1.99999.1.10.1.3.4.101.116.104.48.1.3"},
+	{"Bracketed hex string", "testEntryValue['0a0b'H][1.3]", "1.3.6.1.4.1.99999.1.10.1.3.2.10.11.1.3"},
+	{"Bracketed enum", "ipAddressRowStatus[ipv4][192.0.2.1]", "1.3.6.1.2.1.4.34.1.10.1.4.192.0.2.1"},
+}
+
+func TestParseOID(t *testing.T) {
+	for _, test := range parseOidTests {
+		oid, err := gosmi.ParseOID(test.input)
+		if err != nil {
+			t.Errorf("%s: ParseOID(%q): %v", test.source, test.input, err)
+		} else if oid.String() != test.oid {
+			t.Errorf("%s: ParseOID(%q) = %s, want %s", test.source, test.input, oid, test.oid)
+		}
+	}
+}
+
+func TestParseOIDErrors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"NO-SUCH-MIB::ifDescr",
+		"IF-MIB::1.3",
+		"ifDescr.",
+		"noSuchObject.1",
+		"1.3.4294967296",
+		`ifName."eth0`,
+		"ifDescr[3][4]",
+		"ifDescr[3].1",
+		"ifDescr[eth0]",
+		"ipAdEntIfIndex[192.0.2]",
+	} {
+		if oid, err := gosmi.ParseOID(input); err == nil {
+			t.Errorf("ParseOID(%q) = %s, want error", input, oid)
+		}
+	}
+}
+
+var renderOidTests = []struct {
+	source   string
+	oid      string
+	format   gosmi.OidFormat
+	rendered string
+}{
+	{"Numeric", "1.3.6.1.2.1.2.2.1.2.3", gosmi.OidFormatNumeric, "1.3.6.1.2.1.2.2.1.2.3"},
+	{"Name", "1.3.6.1.2.1.2.2.1.2.3", gosmi.OidFormatName, "ifDescr.3"},
+	{"Qualified", "1.3.6.1.2.1.2.2.1.2.3", gosmi.OidFormatQualified, "IF-MIB::ifDescr.3"},
+	{"Full", "1.3.6.1.2.1.2.2.1.2.3", gosmi.OidFormatFull, ".iso.org.dod.internet.mgmt.mib-2.interfaces.ifTable.ifEntry.ifDescr.3"},
+	{"Index", "1.3.6.1.4.1.99999.1.10.1.3.4.101.116.104.48.1.3", gosmi.OidFormatName | gosmi.OidFormatIndex, `testEntryValue."eth0".1.3`},
+	{"Brackets", "1.3.6.1.4.1.99999.1.10.1.3.4.101.116.104.48.1.3", gosmi.OidFormatQualified | gosmi.OidFormatBrackets, `TEST-MIB::testEntryValue["eth0"][1.3]`},
+	{"IpAddress in brackets", "1.3.6.1.2.1.4.20.1.2.192.0.2.1", gosmi.OidFormatName | gosmi.OidFormatBrackets, "ipAdEntIfIndex[192.0.2.1]"},
+	{"Index that does not decode", "1.3.6.1.4.1.99999.1.10.1.3.9.1", gosmi.OidFormatName | gosmi.OidFormatBrackets, "testEntryValue.9.1"},
+	{"Unknown", "1.3.6.1.4.1.12345.1", gosmi.OidFormatName, "enterprises.12345.1"},
+}
+
+func TestRenderOID(t *testing.T) {
+	for _, test := range renderOidTests {
+		rendered := gosmi.RenderOID(types.OidMustFromString(test.oid), test.format)
+		if rendered != test.rendered {
+			t.Errorf("%s: RenderOID(%s) = %q, want %q", test.source, test.oid, rendered, test.rendered)
+			continue
+		}
+		if test.format == gosmi.OidFormatNumeric {
+			continue
+		}
+		if oid, err := gosmi.ParseOID(rendered); err != nil || oid.String() != test.oid {
+			t.Errorf("%s: ParseOID(%q) = %s, %v, want %s", test.source, rendered, oid, err, test.oid)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package gosmi
+
+import (
+	"sync"
+
+	"github.com/sleepinggenius2/gosmi/smi"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+type ReferenceKind int
+
+const (
+	ReferenceGroup               ReferenceKind = iota // Member of an OBJECT-GROUP or NOTIFICATION-GROUP
+	ReferenceNotification                             // Object of a NOTIFICATION-TYPE or TRAP-TYPE
+	ReferenceRowIndex                                 // INDEX of a row
+	ReferenceAugments                                 // Row augmented by another row
+	ReferenceComplianceMandatory                      // MANDATORY-GROUPS of a MODULE-COMPLIANCE
+	ReferenceComplianceGroup                          // GROUP clause of a MODULE-COMPLIANCE
+	ReferenceComplianceObject                         // OBJECT clause of a MODULE-COMPLIANCE, including its SYNTAX and WRITE-SYNTAX
+	ReferenceSyntax                                   // SYNTAX of an object
+	ReferenceParentType                               // Parent of a type
+)
+
+type Reference struct {
+	Kind ReferenceKind
+	Node SmiNode  // Referencing node, unset for ReferenceParentType
+	Type *SmiType // Referencing type, only set for ReferenceParentType
+}
+
+type nodeReference struct {
+	kind    ReferenceKind
+	smiNode *types.SmiNode
+}
+
+type typeReference struct {
+	kind    ReferenceKind
+	smiNode *types.SmiNode
+	smiType *types.SmiType
+}
+
+// ReferenceIndex maps nodes and types to the definitions that refer to them.
+// Modules are indexed as they are loaded, on the next lookup or call to Update.
+type ReferenceIndex struct {
+	mu      sync.Mutex
+	modules moduleSet
+	nodes   map[*types.SmiNode][]nodeReference
+	types   map[*types.SmiType][]typeReference
+}
+
+func NewReferenceIndex() *ReferenceIndex {
+	return &ReferenceIndex{
+		nodes: make(map[*types.SmiNode][]nodeReference),
+		types: make(map[*types.SmiType][]typeReference),
+	}
+}
+
+var defaultReferenceIndex = NewReferenceIndex()
+
+func (x *ReferenceIndex) addNode(target *types.SmiNode, kind ReferenceKind, smiNode *types.SmiNode) {
+	if target == nil {
+		return
+	}
+	ref := nodeReference{kind: kind, smiNode: smiNode}
+	for _, existing := range x.nodes[target] {
+		if existing == ref {
+			return
+		}
+	}
+	x.nodes[target] = append(x.nodes[target], ref)
+}
+
+func (x *ReferenceIndex) addType(target *types.SmiType, kind ReferenceKind, smiNode *types.SmiNode, smiType *types.SmiType) {
+	// Implicit types, such as Integer32 (0..100), are attributed to the type they restrict
+	for target != nil && target.Decl == types.DeclImplicitType {
+		target = smi.GetParentType(target)
+	}
+	if target == nil {
+		return
+	}
+	ref := typeReference{kind: kind, smiNode: smiNode, smiType: smiType}
+	for _, existing := range x.types[target] {
+		if existing == ref {
+			return
+		}
+	}
+	x.types[target] = append(x.types[target], ref)
+}
+
+func (x *ReferenceIndex) addElements(smiNode *types.SmiNode, kind ReferenceKind) {
+	for smiElement := smi.GetFirstElement(smiNode); smiElement != nil; smiElement = smi.GetNextElement(smiElement) {
+		x.addNode(smi.GetElementNode(smiElement), kind, smiNode)
+	}
+}
+
+func (x *ReferenceIndex) addModule(smiModule *types.SmiModule) {
+	for smiType := smi.GetFirstType(smiModule); smiType != nil; smiType = smi.GetNextType(smiType) {
+		x.addType(smi.GetParentType(smiType), ReferenceParentType, nil, smiType)
+	}
+	for smiNode := smi.GetFirstNode(smiModule, types.NodeAny); smiNode != nil; smiNode = smi.GetNextNode(smiNode, types.NodeAny) {
+		switch smiNode.NodeKind {
+		case types.NodeGroup:
+			x.addElements(smiNode, ReferenceGroup)
+		case types.NodeNotification:
+			x.addElements(smiNode, ReferenceNotification)
+		case types.NodeRow:
+			if smiNode.IndexKind == types.IndexAugment {
+				x.addNode(smi.GetRelatedNode(smiNode), ReferenceAugments, smiNode)
+			} else {
+				x.addElements(smiNode, ReferenceRowIndex)
+			}
+		case types.NodeCompliance:
+			x.addElements(smiNode, ReferenceComplianceMandatory)
+			for smiOption := smi.GetFirstOption(smiNode); smiOption != nil; smiOption = smi.GetNextOption(smiOption) {
+				x.addNode(smi.GetOptionNode(smiOption), ReferenceComplianceGroup, smiNode)
+			}
+			for smiRefinement := smi.GetFirstRefinement(smiNode); smiRefinement != nil; smiRefinement = smi.GetNextRefinement(smiRefinement) {
+				x.addNode(smi.GetRefinementNode(smiRefinement), ReferenceComplianceObject, smiNode)
+				x.addType(smi.GetRefinementType(smiRefinement), ReferenceComplianceObject, smiNode, nil)
+				x.addType(smi.GetRefinementWriteType(smiRefinement), ReferenceComplianceObject, smiNode, nil)
+			}
+		case types.NodeScalar, types.NodeColumn:
+			x.addType(smi.GetNodeType(smiNode), ReferenceSyntax, smiNode, nil)
+		}
+	}
+}
+
+func (x *ReferenceIndex) update() {
+	added, reset := x.modules.update()
+	if reset {
+		x.nodes = make(map[*types.SmiNode][]nodeReference)
+		x.types = make(map[*types.SmiType][]typeReference)
+	}
+	for _, smiModule := range added {
+		x.addModule(smiModule)
+	}
+}
+
+// Update indexes any modules that have been loaded since the last update
+func (x *ReferenceIndex) Update() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.update()
+}
+
+func (x *ReferenceIndex) GetNodeReferences(node SmiNode) (references []Reference) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.update()
+	for _, ref := range x.nodes[node.smiNode] {
+		references = append(references, Reference{Kind: ref.kind, Node: CreateNode(ref.smiNode)})
+	}
+	return
+}
+
+func (x *ReferenceIndex) GetTypeReferences(t SmiType) (references []Reference) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.update()
+	for _, ref := range x.types[t.smiType] {
+		reference := Reference{Kind: ref.kind}
+		if ref.smiNode != nil {
+			reference.Node = CreateNode(ref.smiNode)
+		}
+		if ref.smiType != nil {
+			smiType := CreateType(ref.smiType)
+			reference.Type = &smiType
+		}
+		references = append(references, reference)
+	}
+	return
+}
+
+func (n SmiNode) GetReferences() []Reference {
+	return defaultReferenceIndex.GetNodeReferences(n)
+}
+
+func (t SmiType) GetReferences() []Reference {
+	return defaultReferenceIndex.GetTypeReferences(t)
+}
@@ -0,0 +1,218 @@
+package gosmi_test
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/smi"
+)
+
+var referenceKindNames = map[gosmi.ReferenceKind]string{
+	gosmi.ReferenceGroup:               "Group",
+	gosmi.ReferenceNotification:        "Notification",
+	gosmi.ReferenceRowIndex:            "RowIndex",
+	gosmi.ReferenceAugments:            "Augments",
+	gosmi.ReferenceComplianceMandatory: "ComplianceMandatory",
+	gosmi.ReferenceComplianceGroup:     "ComplianceGroup",
+	gosmi.ReferenceComplianceObject:    "ComplianceObject",
+	gosmi.ReferenceSyntax:              "Syntax",
+	gosmi.ReferenceParentType:          "ParentType",
+}
+
+// referenceNames returns the references sorted in the form "Kind name"
+func referenceNames(references []gosmi.Reference) []string {
+	names := make([]string, 0, len(references))
+	for _, ref := range references {
+		name := ref.Node.Name
+		if ref.Type != nil {
+			name = ref.Type.Name
+		}
+		names = append(names, referenceKindNames[ref.Kind]+" "+name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestNodeReferences(t *testing.T) {
+	tests := []struct {
+		node       string
+		references []string
+	}{
+		{"ifIndex", []string{"Group ifGeneralInformationGroup", "Notification linkDown", "Notification linkUp", "RowIndex ifEntry"}},
+		{"ifEntry", []string{"Augments ifXEntry"}},
+		{"ifAlias", []string{"ComplianceObject ifCompliance3", "ComplianceObject testBrokenCompliance", "ComplianceObject testCompliance", "Group ifCounterGroup"}},
+		{"linkUpDownNotificationsGroup", []string{"ComplianceGroup testCompliance", "ComplianceMandatory ifCompliance3"}},
+		{"testEntryOid", []string{"RowIndex testEntry"}},
+		{"sysDescr", []string{}},
+	}
+	for _, test := range tests {
+		node, err := gosmi.GetNode(test.node)
+		if err != nil {
+			t.Errorf("%s: %v", test.node, err)
+			continue
+		}
+		if names := referenceNames(node.GetReferences()); !equalStrings(names, test.references) {
+			t.Errorf("%s: GetReferences = %v, want %v", test.node, names, test.references)
+		}
+	}
+}
+
+func TestTypeReferences(t *testing.T) {
+	tests := []struct {
+		typeName   string
+		references []string
+	}{
+		{"Temperature", []string{"Syntax testTemperature"}},
+		{"BigGauge", []string{"Syntax testBigGauge"}},
+		{"ShortName", []string{"Syntax testName"}},
+		{"InetAddressType", []string{"Syntax ipAddressAddrType", "Syntax testEntryPeerType"}},
+	}
+	for _, test := range tests {
+		smiType, err := gosmi.GetType(test.typeName)
+		if err != nil {
+			t.Errorf("%s: %v", test.typeName, err)
+			continue
+		}
+		if names := referenceNames(smiType.GetReferences()); !equalStrings(names, test.references) {
+			t.Errorf("%s: GetReferences = %v, want %v", test.typeName, names, test.references)
+		}
+	}
+
+	// The SYNTAX of a refinement is attributed to the type it restricts
+	displayString, err := gosmi.GetType("DisplayString")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := referenceNames(displayString.GetReferences())
+	for _, want := range []string{"ComplianceObject testCompliance", "Syntax ifDescr", "Syntax testEntryName"} {
+		if !contains(names, want) {
+			t.Errorf("DisplayString: GetReferences = %v, want it to include %s", names, want)
+		}
+	}
+}
+
+func TestComplianceRefinements(t *testing.T) {
+	compliance, err := gosmi.GetNode("ifCompliance3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	adminStatus, err := gosmi.GetNode("ifAdminStatus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The SYNTAX INTEGER { up(1), down(2) } excludes testing(3)
+	if err := adminStatus.Validate(3); err != nil {
+		t.Errorf("ifAdminStatus testing(3): %v", err)
+	}
+	if err := adminStatus.Validate(3, compliance); !errors.Is(err, models.ErrUnknownEnum) {
+		t.Errorf("ifAdminStatus testing(3) in ifCompliance3: got error %v, want ErrUnknownEnum", err)
+	}
+	if err := adminStatus.Validate("down", compliance); err != nil {
+		t.Errorf("ifAdminStatus down(2) in ifCompliance3: %v", err)
+	}
+
+	compliance, err = gosmi.GetNode("testCompliance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alias, err := gosmi.GetNode("ifAlias")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The SYNTAX DisplayString (SIZE (0..16)) narrows SIZE (0..64)
+	effective, err := alias.GetEffectiveType(compliance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(effective.Ranges) != 1 || effective.Ranges[0].String() != "0..16" {
+		t.Errorf("ifAlias in testCompliance has ranges %v, want 0..16", effective.Ranges)
+	}
+	if err := alias.Validate(strings.Repeat("x", 17), compliance); !errors.Is(err, models.ErrInvalidSize) {
+		t.Errorf("17 octet ifAlias in testCompliance: got error %v, want ErrInvalidSize", err)
+	}
+}
+
+func TestComplianceUnresolved(t *testing.T) {
+	type report struct {
+		path string
+		line int
+		msg  string
+		tag  string
+	}
+	var reports []report
+	// The errors are only reported when the module is loaded, so it is
+	// loaded again on its own, and the test modules are restored afterwards
+	gosmi.Exit()
+	defer func() {
+		gosmi.Exit()
+		if err := initTestModules(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if err := gosmi.Init(); err != nil {
+		t.Fatal(err)
+	}
+	gosmi.SetPath("testdata/mibs")
+	smi.SetErrorHandler(func(path string, line int, severity int, msg string, tag string) {
+		if tag == "compliance-unresolved" {
+			reports = append(reports, report{path: path, line: line, msg: msg, tag: tag})
+		}
+	})
+	if _, err := gosmi.LoadModule("TEST-COMPLIANCE-MIB"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"Cannot resolve MANDATORY-GROUPS member 'testMissingGroup' from module 'TEST-COMPLIANCE-MIB' in compliance 'testBrokenCompliance'",
+		"Cannot resolve MANDATORY-GROUPS member 'ifMissingGroup' from module 'IF-MIB' in compliance 'testBrokenCompliance'",
+		"Cannot resolve GROUP 'ifMissingOptionalGroup' from module 'IF-MIB' in compliance 'testBrokenCompliance'",
+		"Cannot resolve OBJECT 'ifMissing' from module 'IF-MIB' in compliance 'testBrokenCompliance'",
+		"Cannot load module 'MISSING-MIB' in compliance 'testBrokenCompliance'",
+	}
+	if len(reports) != len(want) {
+		t.Fatalf("Got %d reports %v, want %d", len(reports), reports, len(want))
+	}
+	for i, r := range reports {
+		if r.msg != want[i] || r.tag != "compliance-unresolved" || r.line == 0 || !strings.HasSuffix(r.path, "TEST-COMPLIANCE-MIB") {
+			t.Errorf("Report %d = %+v, want %q", i+1, r, want[i])
+		}
+	}
+
+	// The references that resolve are kept
+	compliance, err := gosmi.GetNode("testBrokenCompliance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counterGroup, err := gosmi.GetNode("ifCounterGroup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := referenceNames(counterGroup.GetReferences()); !equalStrings(names, []string{"ComplianceMandatory testBrokenCompliance"}) {
+		t.Errorf("ifCounterGroup: GetReferences = %v", names)
+	}
+	mtu, err := gosmi.GetNode("ifMtu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mtu.Validate(1500, compliance); err != nil {
+		t.Errorf("ifMtu 1500 in testBrokenCompliance: %v", err)
+	}
+	if err := mtu.Validate(64, compliance); !errors.Is(err, models.ErrOutOfRange) {
+		t.Errorf("ifMtu 64 in testBrokenCompliance: got error %v, want ErrOutOfRange", err)
+	}
+	alias, err := gosmi.GetNode("ifAlias")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The WRITE-SYNTAX is narrower than the SYNTAX
+	if err := alias.Validate(strings.Repeat("x", 32), compliance); err != nil {
+		t.Errorf("32 octet ifAlias in testBrokenCompliance: %v", err)
+	}
+	if err := alias.ValidateWrite(strings.Repeat("x", 9), compliance); !errors.Is(err, models.ErrInvalidSize) {
+		t.Errorf("Set 9 octet ifAlias in testBrokenCompliance: got error %v, want ErrInvalidSize", err)
+	}
+}
@@ -0,0 +1,180 @@
+package gosmi
+
+import (
+	"sort"
+
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// TableRow is a conceptual row assembled from the varbinds of its columns
+type TableRow struct {
+	Instance types.Oid
+	Index    []IndexValue
+	Columns  map[string]models.Value // Values by column name
+	Missing  []string                // Readable columns without a value, as in a sparse table
+}
+
+// TableRows is a table assembled from the varbinds returned by a walk
+type TableRows struct {
+	Columns      []string // Readable columns, including those of merged AUGMENTS tables
+	Inaccessible []string // Columns that cannot be read, with values taken from the index where possible
+	Rows         []TableRow
+	Unmatched    []Varbind // Varbinds that are not instances of a column of the table
+}
+
+func isReadable(access types.Access) bool {
+	return access == types.AccessReadOnly || access == types.AccessReadWrite
+}
+
+// baseRowOid returns the OID of the row that the row augments, or of the row
+// itself if it does not augment another
+func baseRowOid(row SmiNode) types.Oid {
+	if augment := row.GetAugment(); augment.Name != "" {
+		return augment.Oid
+	}
+	return row.Oid
+}
+
+type rowAssembler struct {
+	result   TableRows
+	base     types.Oid
+	tables   map[string]bool // Rows whose columns have been added, by OID
+	columns  map[string]bool // Whether each column belongs to the table, by OID
+	rows     map[string]*TableRow
+	readable map[string]bool
+	pairs    map[string]string // InetAddressType column giving the context of each InetAddress column
+}
+
+func (a *rowAssembler) addColumns(row SmiNode) {
+	key := oidKey(row.Oid)
+	if a.tables[key] {
+		return
+	}
+	a.tables[key] = true
+	columns, columnOrder := row.GetColumns()
+	var addrType string
+	for _, name := range columnOrder {
+		if t := columns[name].Type; t != nil {
+			// RFC 4001 suggests registering the InetAddressType object before
+			// the InetAddress objects that it applies to
+			if t.DerivesFrom("INET-ADDRESS-MIB::InetAddressType") {
+				addrType = name
+			} else if addrType != "" && t.DerivesFrom("INET-ADDRESS-MIB::InetAddress") {
+				a.pairs[name] = addrType
+			}
+		}
+		if isReadable(columns[name].Access) {
+			a.result.Columns = append(a.result.Columns, name)
+			a.readable[name] = true
+		} else {
+			a.result.Inaccessible = append(a.result.Inaccessible, name)
+		}
+	}
+}
+
+func (a *rowAssembler) belongs(column SmiNode) bool {
+	key := oidKey(column.Oid)
+	if belongs, ok := a.columns[key]; ok {
+		return belongs
+	}
+	row := column.GetRow()
+	belongs := row.Name != "" && baseRowOid(row).Equals(a.base)
+	a.columns[key] = belongs
+	if belongs {
+		a.addColumns(row)
+	}
+	return belongs
+}
+
+func (a *rowAssembler) add(vb Varbind) {
+	if vb.Kind != types.NodeColumn || vb.Err != nil || !a.belongs(vb.Node) {
+		a.result.Unmatched = append(a.result.Unmatched, vb)
+		return
+	}
+	key := oidKey(vb.Instance)
+	row, ok := a.rows[key]
+	if !ok {
+		row = &TableRow{
+			Instance: vb.Instance,
+			Index:    vb.Index,
+			Columns:  make(map[string]models.Value),
+		}
+		a.rows[key] = row
+	}
+	row.Columns[vb.Node.Name] = vb.Value
+}
+
+// pairInetAddresses formats the InetAddress values of the row, including those
+// in the index, according to their InetAddressType
+func (a *rowAssembler) pairInetAddresses(r *TableRow) {
+	for addrName, typeName := range a.pairs {
+		addr, ok := r.Columns[addrName]
+		addrType, typeOk := r.Columns[typeName]
+		if ok && typeOk {
+			r.Columns[addrName] = models.PairInetAddress(addr, addrType)
+		}
+	}
+	var index []IndexValue
+	for i, value := range r.Index {
+		addrType, ok := r.Columns[a.pairs[value.Node.Name]]
+		if !ok {
+			continue
+		}
+		if index == nil {
+			// The index is shared with the varbinds of the row
+			index = append([]IndexValue(nil), r.Index...)
+		}
+		index[i].Value = models.PairInetAddress(value.Value, addrType)
+	}
+	if index != nil {
+		r.Index = index
+	}
+}
+
+// AssembleRows groups the varbinds from walking the table into rows, keyed by
+// their decoded index and in index order. The columns of tables that augment
+// the table, or that the table augments, are merged into the same rows.
+// Columns that cannot be read, such as not-accessible index objects, are
+// filled in from the index. InetAddress values are formatted according to the
+// InetAddressType column registered before them.
+func (t Table) AssembleRows(varbinds []Varbind) TableRows {
+	row := t.GetRow()
+	a := rowAssembler{
+		tables:   make(map[string]bool),
+		columns:  make(map[string]bool),
+		rows:     make(map[string]*TableRow),
+		readable: make(map[string]bool),
+		pairs:    make(map[string]string),
+	}
+	if row.Name == "" {
+		a.result.Unmatched = varbinds
+		return a.result
+	}
+	a.base = baseRowOid(row)
+	a.addColumns(row)
+	for _, vb := range varbinds {
+		a.add(vb)
+	}
+	a.result.Rows = make([]TableRow, 0, len(a.rows))
+	for _, r := range a.rows {
+		// Only once all varbinds are added are the columns of every merged
+		// table known to be readable or not
+		for _, index := range r.Index {
+			if !a.readable[index.Node.Name] {
+				r.Columns[index.Node.Name] = index.Value
+			}
+		}
+		a.pairInetAddresses(r)
+		for _, name := range a.result.Columns {
+			if _, ok := r.Columns[name]; !ok {
+				r.Missing = append(r.Missing, name)
+			}
+		}
+		a.result.Rows = append(a.result.Rows, *r)
+	}
+	sort.Slice(a.result.Rows, func(i, j int) bool {
+		return a.result.Rows[i].Instance.Before(a.result.Rows[j].Instance)
+	})
+	return a.result
+}
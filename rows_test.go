@@ -0,0 +1,182 @@
+package gosmi_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+func getTable(t *testing.T, name string) gosmi.Table {
+	t.Helper()
+	node, err := gosmi.GetNode(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return node.AsTable()
+}
+
+func decodeVarbinds(t *testing.T, values map[string]interface{}) []gosmi.Varbind {
+	t.Helper()
+	varbinds := make([]gosmi.Varbind, 0, len(values))
+	for oid, raw := range values {
+		vb, err := gosmi.DecodeVarbind(types.OidMustFromString(oid), raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		varbinds = append(varbinds, vb)
+	}
+	return varbinds
+}
+
+func TestAssembleRowsInetAddress(t *testing.T) {
+	// ipAddressIfIndex of fe80::1%4, whose index has the type ipv6z and the
+	// address with its length
+	ipv6z := "4.20.254.128.0.0.0.0.0.0.0.0.0.0.0.0.0.1.0.0.0.4"
+	rows := getTable(t, "ipAddressTable").AssembleRows(decodeVarbinds(t, map[string]interface{}{
+		"1.3.6.1.2.1.4.34.1.3." + ipv6z: 7,
+	}))
+	if len(rows.Rows) != 1 {
+		t.Fatalf("Got %d rows, want 1", len(rows.Rows))
+	}
+	row := rows.Rows[0]
+	addr, ok := row.Columns["ipAddressAddr"].Raw.(models.InetAddress)
+	if !ok || addr.Type != models.InetAddressTypeIPv6z || addr.Zone != 4 || row.Columns["ipAddressAddr"].Formatted != "fe80::1%4" {
+		t.Errorf("ipAddressAddr = %q with %#v", row.Columns["ipAddressAddr"].Formatted, row.Columns["ipAddressAddr"].Raw)
+	}
+	if len(row.Index) != 2 || row.Index[1].Value.Formatted != "fe80::1%4" {
+		t.Errorf("Index = %v, want ipv6z and fe80::1%%4", row.Index)
+	}
+
+	// testEntryPeer of "a".1.3 is a DNS name of 8 octets, which is otherwise
+	// taken for ipv4z
+	rows = getTable(t, "testTable").AssembleRows(decodeVarbinds(t, map[string]interface{}{
+		"1.3.6.1.4.1.99999.1.10.1.5.1.97.1.3": 16,
+		"1.3.6.1.4.1.99999.1.10.1.6.1.97.1.3": []byte("peer.net"),
+		"1.3.6.1.4.1.99999.1.10.1.6.1.98.1.3": []byte("peer.net"),
+	}))
+	if len(rows.Rows) != 2 {
+		t.Fatalf("Got %d rows, want 2", len(rows.Rows))
+	}
+	if peer := rows.Rows[0].Columns["testEntryPeer"]; peer.Formatted != "peer.net" {
+		t.Errorf("testEntryPeer with type dns = %q with %#v", peer.Formatted, peer.Raw)
+	}
+	if peer := rows.Rows[1].Columns["testEntryPeer"]; peer.Raw.(models.InetAddress).Type != models.InetAddressTypeIPv4z {
+		t.Errorf("testEntryPeer without type = %q with %#v, want it inferred from the length", peer.Formatted, peer.Raw)
+	}
+}
+
+func TestAssembleRowsAugments(t *testing.T) {
+	varbinds := decodeVarbinds(t, map[string]interface{}{
+		"1.3.6.1.2.1.1.3.0":            100,
+		"1.3.6.1.2.1.2.2.1.2.1":        []byte("eth0"),
+		"1.3.6.1.2.1.2.2.1.2.2":        []byte("eth1"),
+		"1.3.6.1.2.1.2.2.1.8.1":        1,
+		"1.3.6.1.2.1.31.1.1.1.1.1":     []byte("e0"),
+		"1.3.6.1.2.1.31.1.1.1.1.2":     []byte("e1"),
+		"1.3.6.1.2.1.31.1.1.1.6.1":     uint64(1 << 40),
+		"1.3.6.1.2.1.4.20.1.2.1.2.3.4": 1,
+	})
+	ifColumns := []string{"ifIndex", "ifDescr", "ifMtu", "ifSpeed", "ifPhysAddress", "ifAdminStatus", "ifOperStatus", "ifLastChange", "ifInOctets"}
+	ifXColumns := []string{"ifName", "ifHCInOctets", "ifAlias"}
+	tests := []struct {
+		source  string
+		table   string
+		columns []string
+	}{
+		{"Augmented", "ifTable", append(append([]string(nil), ifColumns...), ifXColumns...)},
+		{"Augmenting", "ifXTable", append(append([]string(nil), ifXColumns...), ifColumns...)},
+	}
+	for _, test := range tests {
+		rows := getTable(t, test.table).AssembleRows(varbinds)
+		if !reflect.DeepEqual(rows.Columns, test.columns) {
+			t.Errorf("%s: Columns = %v, want %v", test.source, rows.Columns, test.columns)
+		}
+		if len(rows.Inaccessible) != 0 {
+			t.Errorf("%s: Inaccessible = %v, want none", test.source, rows.Inaccessible)
+		}
+		if len(rows.Unmatched) != 2 {
+			t.Errorf("%s: got %d unmatched varbinds, want sysUpTime and ipAdEntIfIndex", test.source, len(rows.Unmatched))
+		}
+		if len(rows.Rows) != 2 {
+			t.Errorf("%s: got %d rows, want 2", test.source, len(rows.Rows))
+			continue
+		}
+		first, second := rows.Rows[0], rows.Rows[1]
+		if first.Instance.String() != "1" || second.Instance.String() != "2" {
+			t.Errorf("%s: rows are for instances %s and %s, want 1 and 2", test.source, first.Instance, second.Instance)
+		}
+		if first.Columns["ifDescr"].Formatted != "eth0" || first.Columns["ifName"].Formatted != "e0" || first.Columns["ifHCInOctets"].Raw != uint64(1<<40) {
+			t.Errorf("%s: first row = %v", test.source, first.Columns)
+		}
+		if len(first.Index) != 1 || first.Index[0].Node.Name != "ifIndex" || first.Index[0].Value.Raw != int64(1) {
+			t.Errorf("%s: first row has index %v, want ifIndex 1", test.source, first.Index)
+		}
+
+		// The second row is sparse
+		if len(second.Columns) != 2 || second.Columns["ifName"].Formatted != "e1" {
+			t.Errorf("%s: second row = %v, want ifDescr and ifName", test.source, second.Columns)
+		}
+		var wantMissing []string
+		for _, name := range test.columns {
+			if name != "ifDescr" && name != "ifName" {
+				wantMissing = append(wantMissing, name)
+			}
+		}
+		if !reflect.DeepEqual(second.Missing, wantMissing) {
+			t.Errorf("%s: second row is missing %v, want %v", test.source, second.Missing, wantMissing)
+		}
+		if contains(first.Missing, "ifOperStatus") || !contains(first.Missing, "ifAlias") {
+			t.Errorf("%s: first row is missing %v", test.source, first.Missing)
+		}
+	}
+}
+
+func TestAssembleRowsInaccessible(t *testing.T) {
+	rows := getTable(t, "testTable").AssembleRows(decodeVarbinds(t, map[string]interface{}{
+		"1.3.6.1.4.1.99999.1.10.1.3.1.97.1.3":    42,
+		"1.3.6.1.4.1.99999.1.10.1.3.2.98.99.1.5": 7,
+		"1.3.6.1.4.1.99999.1.10.1.4.2.98.99.1.5": 1,
+	}))
+	if want := []string{"testEntryName", "testEntryOid"}; !reflect.DeepEqual(rows.Inaccessible, want) {
+		t.Errorf("Inaccessible = %v, want %v", rows.Inaccessible, want)
+	}
+	if len(rows.Rows) != 2 {
+		t.Fatalf("Got %d rows, want 2", len(rows.Rows))
+	}
+	tests := []struct {
+		name    string
+		oid     string
+		value   int64
+		missing []string
+	}{
+		{"a", "1.3", 42, []string{"testEntryStatus", "testEntryPeerType", "testEntryPeer"}},
+		{"bc", "1.5", 7, []string{"testEntryPeerType", "testEntryPeer"}},
+	}
+	for i, test := range tests {
+		row := rows.Rows[i]
+		if name := string(row.Columns["testEntryName"].Bytes()); name != test.name {
+			t.Errorf("Row %d: testEntryName = %q, want %q", i+1, name, test.name)
+		}
+		if oid, err := models.ToOid(row.Columns["testEntryOid"].Raw); err != nil || oid.String() != test.oid {
+			t.Errorf("Row %d: testEntryOid = %v, %v, want %s", i+1, oid, err, test.oid)
+		}
+		if value := row.Columns["testEntryValue"].Int64(); value != test.value {
+			t.Errorf("Row %d: testEntryValue = %d, want %d", i+1, value, test.value)
+		}
+		if !reflect.DeepEqual(row.Missing, test.missing) {
+			t.Errorf("Row %d: Missing = %v, want %v", i+1, row.Missing, test.missing)
+		}
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,394 @@
+package gosmi
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/sleepinggenius2/gosmi/smi"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+type SearchMode int
+
+const (
+	SearchToken  SearchMode = iota // Ranked match on the words of the query
+	SearchPrefix                   // Case-insensitive prefix match
+	SearchGlob                     // Case-insensitive match on the whole field with * and ? wildcards
+	SearchRegexp                   // Regular expression match anywhere in the field
+)
+
+type SearchField int
+
+const (
+	SearchFieldName SearchField = 1 << iota
+	SearchFieldModule
+	SearchFieldDescription
+	SearchFieldReference
+	SearchFieldUnits
+	SearchFieldType
+
+	SearchFieldAll SearchField = SearchFieldName | SearchFieldModule | SearchFieldDescription | SearchFieldReference | SearchFieldUnits | SearchFieldType
+)
+
+var searchFields = []SearchField{
+	SearchFieldName,
+	SearchFieldModule,
+	SearchFieldDescription,
+	SearchFieldReference,
+	SearchFieldUnits,
+	SearchFieldType,
+}
+
+var searchFieldWeights = map[SearchField]float64{
+	SearchFieldName:        4,
+	SearchFieldModule:      1,
+	SearchFieldDescription: 1,
+	SearchFieldReference:   0.5,
+	SearchFieldUnits:       2,
+	SearchFieldType:        2,
+}
+
+type SearchOptions struct {
+	Mode   SearchMode
+	Fields SearchField // Fields to match, defaults to SearchFieldName for prefix and glob, and SearchFieldAll otherwise
+	Limit  int         // Maximum number of results, 0 for no limit
+}
+
+type SearchHighlight struct {
+	Field SearchField
+	Text  string // Full text of the field
+	Start int    // Byte offset of the match in Text
+	End   int
+}
+
+type SearchResult struct {
+	Node       SmiNode
+	Score      float64
+	Highlights []SearchHighlight
+}
+
+type searchToken struct {
+	value      string
+	start, end int
+}
+
+type searchPosting struct {
+	entry int
+	field SearchField
+	count int
+}
+
+type searchEntry struct {
+	smiNode   *types.SmiNode
+	module    string
+	typeNames string
+}
+
+func (e searchEntry) field(field SearchField) string {
+	switch field {
+	case SearchFieldName:
+		return string(e.smiNode.Name)
+	case SearchFieldModule:
+		return e.module
+	case SearchFieldDescription:
+		return e.smiNode.Description
+	case SearchFieldReference:
+		return e.smiNode.Reference
+	case SearchFieldUnits:
+		return e.smiNode.Units
+	case SearchFieldType:
+		return e.typeNames
+	}
+	return ""
+}
+
+// SearchIndex is a search index over the nodes of all loaded modules. Modules
+// are indexed as they are loaded, on the next call to Update or Search.
+type SearchIndex struct {
+	mu       sync.Mutex
+	modules  moduleSet
+	entries  []searchEntry
+	postings map[string][]searchPosting
+}
+
+func NewSearchIndex() *SearchIndex {
+	return &SearchIndex{
+		postings: make(map[string][]searchPosting),
+	}
+}
+
+var defaultSearchIndex = NewSearchIndex()
+
+func Search(query string, options ...SearchOptions) ([]SearchResult, error) {
+	return defaultSearchIndex.Search(query, options...)
+}
+
+func stemToken(token string) string {
+	switch {
+	case len(token) > 4 && strings.HasSuffix(token, "ies"):
+		return token[:len(token)-3] + "y"
+	case len(token) > 3 && strings.HasSuffix(token, "s") && !strings.HasSuffix(token, "ss"):
+		return token[:len(token)-1]
+	}
+	return token
+}
+
+// tokenize splits s into lower-cased words, also splitting camelCase
+// descriptors so that ifInOctets yields if, in, octets and ifinoctets
+func tokenize(s string) (tokens []searchToken) {
+	addToken := func(start, end int) {
+		tokens = append(tokens, searchToken{value: stemToken(strings.ToLower(s[start:end])), start: start, end: end})
+	}
+	wordStart := -1
+	partStart := -1
+	parts := 0
+	var prev rune
+	flush := func(end int) {
+		if wordStart < 0 {
+			return
+		}
+		addToken(partStart, end)
+		if parts > 0 {
+			addToken(wordStart, end)
+		}
+		wordStart, partStart, parts = -1, -1, 0
+	}
+	for i, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush(i)
+			prev = r
+			continue
+		}
+		if wordStart < 0 {
+			wordStart, partStart = i, i
+		} else if (unicode.IsUpper(r) && unicode.IsLower(prev)) || (unicode.IsDigit(r) != unicode.IsDigit(prev)) {
+			addToken(partStart, i)
+			partStart = i
+			parts++
+		}
+		prev = r
+	}
+	flush(len(s))
+	return
+}
+
+// lowerPrefixLength returns the length of the prefix of text that lower-cases
+// to prefix, or -1 if there is none. Lower-casing can change the length of a
+// rune, so the length of prefix cannot be used as an offset in text.
+func lowerPrefixLength(text, prefix string) int {
+	var lower string
+	for i, r := range text {
+		if lower == prefix {
+			return i
+		}
+		if !strings.HasPrefix(prefix, lower) {
+			return -1
+		}
+		lower += strings.ToLower(string(r))
+	}
+	if lower == prefix {
+		return len(text)
+	}
+	return -1
+}
+
+func typeNames(smiType *types.SmiType) string {
+	var names []string
+	for ; smiType != nil; smiType = smi.GetParentType(smiType) {
+		if smiType.Name != "" {
+			names = append(names, string(smiType.Name))
+		}
+	}
+	return strings.Join(names, " ")
+}
+
+func (x *SearchIndex) addEntry(entry searchEntry) {
+	i := len(x.entries)
+	x.entries = append(x.entries, entry)
+	for _, field := range searchFields {
+		counts := make(map[string]int)
+		for _, token := range tokenize(entry.field(field)) {
+			counts[token.value]++
+		}
+		for token, count := range counts {
+			x.postings[token] = append(x.postings[token], searchPosting{entry: i, field: field, count: count})
+		}
+	}
+}
+
+func (x *SearchIndex) update() {
+	added, reset := x.modules.update()
+	if reset {
+		x.entries = nil
+		x.postings = make(map[string][]searchPosting)
+	}
+	for _, smiModule := range added {
+		for smiNode := smi.GetFirstNode(smiModule, types.NodeAny); smiNode != nil; smiNode = smi.GetNextNode(smiNode, types.NodeAny) {
+			if smiNode.Name == "" {
+				continue
+			}
+			x.addEntry(searchEntry{
+				smiNode:   smiNode,
+				module:    string(smiModule.Name),
+				typeNames: typeNames(smi.GetNodeType(smiNode)),
+			})
+		}
+	}
+}
+
+// Update indexes any modules that have been loaded since the last update
+func (x *SearchIndex) Update() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.update()
+}
+
+func (x *SearchIndex) Search(query string, options ...SearchOptions) (results []SearchResult, err error) {
+	var o SearchOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	if o.Fields == 0 {
+		if o.Mode == SearchPrefix || o.Mode == SearchGlob {
+			o.Fields = SearchFieldName
+		} else {
+			o.Fields = SearchFieldAll
+		}
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.update()
+
+	switch o.Mode {
+	case SearchToken:
+		results = x.searchTokens(query, o.Fields)
+	case SearchPrefix:
+		query = strings.ToLower(query)
+		results = x.searchMatch(o.Fields, func(text string) [][]int {
+			if end := lowerPrefixLength(text, query); end >= 0 {
+				return [][]int{{0, end}}
+			}
+			return nil
+		})
+	case SearchGlob:
+		query = strings.ToLower(query)
+		if _, err = path.Match(query, ""); err != nil {
+			return nil, fmt.Errorf("Invalid glob pattern %q: %w", query, err)
+		}
+		results = x.searchMatch(o.Fields, func(text string) [][]int {
+			if ok, _ := path.Match(query, strings.ToLower(text)); ok {
+				return [][]int{{0, len(text)}}
+			}
+			return nil
+		})
+	case SearchRegexp:
+		var re *regexp.Regexp
+		re, err = regexp.Compile(query)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid regular expression %q: %w", query, err)
+		}
+		results = x.searchMatch(o.Fields, func(text string) [][]int {
+			return re.FindAllStringIndex(text, -1)
+		})
+	default:
+		return nil, fmt.Errorf("Unknown search mode %d", o.Mode)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if !results[i].Node.Oid.Equals(results[j].Node.Oid) {
+			return results[i].Node.Oid.Before(results[j].Node.Oid)
+		}
+		return results[i].Node.Name < results[j].Node.Name
+	})
+	if o.Limit > 0 && len(results) > o.Limit {
+		results = results[:o.Limit]
+	}
+	return
+}
+
+func (x *SearchIndex) searchMatch(fields SearchField, match func(text string) [][]int) (results []SearchResult) {
+	for _, entry := range x.entries {
+		var highlights []SearchHighlight
+		var score float64
+		for _, field := range searchFields {
+			if fields&field == 0 {
+				continue
+			}
+			text := entry.field(field)
+			if text == "" {
+				continue
+			}
+			matches := match(text)
+			if len(matches) > 0 {
+				score += searchFieldWeights[field]
+			}
+			for _, m := range matches {
+				highlights = append(highlights, SearchHighlight{Field: field, Text: text, Start: m[0], End: m[1]})
+			}
+		}
+		if len(highlights) > 0 {
+			results = append(results, SearchResult{Node: CreateNode(entry.smiNode), Score: score, Highlights: highlights})
+		}
+	}
+	return
+}
+
+func (x *SearchIndex) searchTokens(query string, fields SearchField) (results []SearchResult) {
+	queryTokens := make(map[string]bool)
+	for _, token := range tokenize(query) {
+		queryTokens[token.value] = true
+	}
+	if len(queryTokens) == 0 || len(x.entries) == 0 {
+		return
+	}
+	scores := make(map[int]float64)
+	matched := make(map[int]int)
+	for token := range queryTokens {
+		postings := x.postings[token]
+		if len(postings) == 0 {
+			continue
+		}
+		idf := math.Log(1 + float64(len(x.entries))/float64(len(postings)))
+		seen := make(map[int]bool)
+		for _, posting := range postings {
+			if fields&posting.field == 0 {
+				continue
+			}
+			scores[posting.entry] += idf * searchFieldWeights[posting.field] * (1 + math.Log(float64(posting.count)))
+			if !seen[posting.entry] {
+				seen[posting.entry] = true
+				matched[posting.entry]++
+			}
+		}
+	}
+	for i, score := range scores {
+		entry := x.entries[i]
+		result := SearchResult{
+			Node:  CreateNode(entry.smiNode),
+			Score: score * float64(matched[i]) / float64(len(queryTokens)),
+		}
+		for _, field := range searchFields {
+			if fields&field == 0 {
+				continue
+			}
+			text := entry.field(field)
+			for _, token := range tokenize(text) {
+				if queryTokens[token.value] {
+					result.Highlights = append(result.Highlights, SearchHighlight{Field: field, Text: text, Start: token.start, End: token.end})
+				}
+			}
+		}
+		results = append(results, result)
+	}
+	return
+}
@@ -0,0 +1,83 @@
+package gosmi_test
+
+import (
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi"
+)
+
+// searchHighlights returns the results in the form "node: highlighted text..."
+func searchHighlights(results []gosmi.SearchResult) []string {
+	highlights := make([]string, len(results))
+	for i, result := range results {
+		highlights[i] = result.Node.Name + ":"
+		for _, h := range result.Highlights {
+			highlights[i] += " " + h.Text[h.Start:h.End]
+		}
+	}
+	return highlights
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		source     string
+		query      string
+		options    gosmi.SearchOptions
+		highlights []string
+	}{
+		{"Prefix", "ifhc", gosmi.SearchOptions{Mode: gosmi.SearchPrefix}, []string{"ifHCInOctets: ifHC"}},
+		{"Glob", "if*octets", gosmi.SearchOptions{Mode: gosmi.SearchGlob}, []string{"ifInOctets: ifInOctets", "ifHCInOctets: ifHCInOctets"}},
+		{"Regexp", "^ifAdmin|Octets$", gosmi.SearchOptions{Mode: gosmi.SearchRegexp, Fields: gosmi.SearchFieldName}, []string{"ifAdminStatus: ifAdmin", "ifInOctets: Octets", "ifHCInOctets: Octets"}},
+		// Ranked by the number of query words matched and the fields that match
+		{"Token", "in octets", gosmi.SearchOptions{Limit: 2}, []string{"ifInOctets: In Octets octets octets", "ifMtu: in octets octets"}},
+		{"Token in units", "octets", gosmi.SearchOptions{Fields: gosmi.SearchFieldUnits}, []string{"ifMtu: octets", "ifInOctets: octets", "ifHCInOctets: octets"}},
+		{"No match", "nosuchobject", gosmi.SearchOptions{}, []string{}},
+	}
+	for _, test := range tests {
+		results, err := gosmi.Search(test.query, test.options)
+		if err != nil {
+			t.Errorf("%s: Search(%q): %v", test.source, test.query, err)
+			continue
+		}
+		if highlights := searchHighlights(results); !equalStrings(highlights, test.highlights) {
+			t.Errorf("%s: Search(%q) = %q, want %q", test.source, test.query, highlights, test.highlights)
+		}
+	}
+
+	results, err := gosmi.Search("temperature")
+	if err != nil || len(results) == 0 || results[0].Node.Name != "testTemperature" {
+		t.Errorf("Search(temperature) = %q, %v, want testTemperature first", searchHighlights(results), err)
+	}
+
+	for _, options := range []gosmi.SearchOptions{{Mode: gosmi.SearchGlob}, {Mode: gosmi.SearchRegexp}, {Mode: gosmi.SearchMode(99)}} {
+		if _, err := gosmi.Search("[if", options); err == nil {
+			t.Errorf("Search([if) in mode %d: got no error", options.Mode)
+		}
+	}
+}
+
+func TestSearchFoldedHighlight(t *testing.T) {
+	// İ and the Kelvin sign are shorter when lower-cased, so the highlights
+	// cannot be taken from the lower-cased query
+	tests := []struct {
+		query      string
+		field      gosmi.SearchField
+		highlights []string
+	}{
+		{"İZ", gosmi.SearchFieldDescription, []string{"testSearchOffice: İz"}},
+		{"izmir o", gosmi.SearchFieldDescription, []string{"testSearchOffice: İzmir o"}},
+		{"ke", gosmi.SearchFieldUnits, []string{"testSearchOffice: Ke"}},
+		{"kelvin", gosmi.SearchFieldUnits, []string{"testSearchOffice: Kelvin"}},
+		{"office", gosmi.SearchFieldDescription, []string{}},
+	}
+	for _, test := range tests {
+		results, err := gosmi.Search(test.query, gosmi.SearchOptions{Mode: gosmi.SearchPrefix, Fields: test.field})
+		if err != nil {
+			t.Errorf("Search(%q): %v", test.query, err)
+			continue
+		}
+		if highlights := searchHighlights(results); !equalStrings(highlights, test.highlights) {
+			t.Errorf("Search(%q) = %q, want %q", test.query, highlights, test.highlights)
+		}
+	}
+}
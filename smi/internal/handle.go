@@ -77,6 +77,14 @@ func SetErrorHandler(smiErrorHandler types.SmiErrorHandler) {
 	smiHandle.ErrorHandler = smiErrorHandler
 }
 
+// reportError passes an error found while building a module to the error
+// handler, if one is set
+func reportError(path string, line int, severity int, msg string, tag string) {
+	if smiHandle != nil && smiHandle.ErrorHandler != nil {
+		smiHandle.ErrorHandler(path, line, severity, msg, tag)
+	}
+}
+
 func SetSeverity(pattern string, severity int) {}
 
 func SetErrorLevel(level int) {}
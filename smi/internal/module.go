@@ -381,6 +381,160 @@ func (x *columnMap) CheckAndRemove(name types.SmiIdentifier) bool {
 	return ok
 }
 
+func (x *Module) getSyntaxType(syntax parser.SyntaxType, status types.Status) *Type {
+	parentType := GetBaseTypeFromSyntax(syntax)
+	if parentType == nil {
+		parentType = x.GetType(syntax.Name)
+		if parentType == nil {
+			// What do we do here?
+			return nil
+		}
+	}
+	if syntax.SubType == nil && len(syntax.Enum) == 0 {
+		return parentType
+	}
+	currType := &Type{
+		SmiType: types.SmiType{
+			BaseType: parentType.BaseType,
+			Decl:     types.DeclImplicitType,
+			Status:   status,
+		},
+		Module: x,
+		Parent: parentType,
+		Line:   syntax.Pos.Line,
+	}
+	baseType := currType.BaseType
+	if syntax.SubType != nil {
+		var ranges []parser.Range
+		if baseType == types.BaseTypeOctetString {
+			ranges = syntax.SubType.OctetString
+			baseType = types.BaseTypeUnsigned32
+		} else {
+			ranges = syntax.SubType.Integer
+		}
+		rangeSort(ranges)
+		for _, r := range ranges {
+			if r.End == "" {
+				r.End = r.Start
+			}
+			currType.AddRange(GetValue(r.Start, baseType), GetValue(r.End, baseType))
+		}
+	} else if len(syntax.Enum) > 0 {
+		if baseType == types.BaseTypeEnum {
+			if parentType.List == nil || parentType.List.Ptr == nil {
+				// TODO: Figure out a better option. This should never happen.
+				baseType = types.BaseTypeInteger32
+			} else {
+				baseType = parentType.List.Ptr.(*NamedNumber).Value.BaseType
+			}
+		} else if baseType == types.BaseTypeBits {
+			baseType = types.BaseTypeUnsigned32
+		}
+		namedNumberSort(syntax.Enum)
+		for _, nn := range syntax.Enum {
+			currType.AddNamedNumber(nn.Name, GetValue(nn.Value, baseType))
+		}
+		if currType.BaseType == types.BaseTypeBits {
+			if parentType == smiHandle.TypeBits {
+				currType.Name = "Bits"
+			} else {
+				currType.Name = parentType.Name
+			}
+		} else {
+			if parentType.Module == nil || parentType.Module.IsWellKnown() {
+				currType.Name = "Enumeration"
+			} else {
+				currType.Name = parentType.Name
+			}
+			currType.BaseType = types.BaseTypeEnum
+		}
+	}
+	return currType
+}
+
+type compliance struct {
+	object  *Object
+	modules []parser.ModuleComplianceModule
+}
+
+// addCompliance adds the clauses of a MODULE-COMPLIANCE once all objects of
+// the module are defined. References that cannot be resolved are reported to
+// the error handler and left out.
+func (x *Module) addCompliance(c compliance) {
+	unresolved := func(line int, clause string, name types.SmiIdentifier, module types.SmiIdentifier) {
+		msg := fmt.Sprintf("Cannot resolve %s '%s' from module '%s' in compliance '%s'", clause, name, module, c.object.Name)
+		reportError(x.Path, line, 2, msg, "compliance-unresolved")
+	}
+	for _, m := range c.modules {
+		modulePtr := x
+		if m.Name != "" && types.SmiIdentifier(m.Name) != x.Name {
+			modulePtr, _ = GetModule(string(m.Name))
+			if modulePtr == nil {
+				msg := fmt.Sprintf("Cannot load module '%s' in compliance '%s'", m.Name, c.object.Name)
+				reportError(x.Path, c.object.Line, 2, msg, "compliance-unresolved")
+				continue
+			}
+		}
+		getObject := func(name types.SmiIdentifier) *Object {
+			if obj := modulePtr.Objects.Get(name); obj != nil || modulePtr != x {
+				return obj
+			}
+			if x.Imports.Get(name) == nil {
+				return nil
+			}
+			return x.GetObject(name)
+		}
+		for _, name := range m.MandatoryGroups {
+			if obj := getObject(name); obj != nil {
+				c.object.AddElement(obj)
+			} else {
+				unresolved(c.object.Line, "MANDATORY-GROUPS member", name, modulePtr.Name)
+			}
+		}
+		for _, clause := range m.Compliances {
+			if clause.Group != nil {
+				obj := getObject(clause.Group.Name)
+				if obj == nil {
+					unresolved(clause.Group.Pos.Line, "GROUP", clause.Group.Name, modulePtr.Name)
+					continue
+				}
+				c.object.AddOption(&Option{
+					SmiOption: types.SmiOption{
+						Description: clause.Group.Description,
+					},
+					Compliance: c.object,
+					Object:     obj,
+					Line:       clause.Group.Pos.Line,
+				})
+			} else if clause.Object != nil {
+				obj := getObject(clause.Object.Name)
+				if obj == nil {
+					unresolved(clause.Object.Pos.Line, "OBJECT", clause.Object.Name, modulePtr.Name)
+					continue
+				}
+				refinement := &Refinement{
+					SmiRefinement: types.SmiRefinement{
+						Description: clause.Object.Description,
+					},
+					Compliance: c.object,
+					Object:     obj,
+					Line:       clause.Object.Pos.Line,
+				}
+				if clause.Object.MinAccess != nil {
+					refinement.Access = clause.Object.MinAccess.ToSmi()
+				}
+				if clause.Object.Syntax != nil && clause.Object.Syntax.Type != nil {
+					refinement.Type = x.getSyntaxType(*clause.Object.Syntax.Type, c.object.Status)
+				}
+				if clause.Object.WriteSyntax != nil && clause.Object.WriteSyntax.Type != nil {
+					refinement.WriteType = x.getSyntaxType(*clause.Object.WriteSyntax.Type, c.object.Status)
+				}
+				c.object.AddRefinement(refinement)
+			}
+		}
+	}
+}
+
 func BuildModule(path string, in *parser.Module) (*Module, error) {
 	var columnMap columnMap
 	out := &Module{
@@ -527,6 +681,7 @@ func BuildModule(path string, in *parser.Module) (*Module, error) {
 	}
 
 	var currObject *Object
+	var compliances []compliance
 	for _, node := range in.Body.Nodes {
 		currObject = out.getPending(node.Name)
 		if currObject == nil {
@@ -583,76 +738,7 @@ func BuildModule(path string, in *parser.Module) (*Module, error) {
 				} else {
 					currObject.NodeKind = types.NodeScalar
 				}
-				syntax := *objType.Syntax.Type
-				parentType := GetBaseTypeFromSyntax(syntax)
-				if parentType == nil {
-					parentType = out.GetType(syntax.Name)
-					if parentType == nil {
-						// What do we do here?
-						break
-					}
-				}
-				if syntax.SubType == nil && len(syntax.Enum) == 0 {
-					currObject.Type = parentType
-					break
-				}
-				currType = &Type{
-					SmiType: types.SmiType{
-						BaseType: parentType.BaseType,
-						Decl:     types.DeclImplicitType,
-						Status:   currObject.Status,
-					},
-					Module: out,
-					Parent: parentType,
-					Line:   syntax.Pos.Line,
-				}
-				baseType := currType.BaseType
-				if syntax.SubType != nil {
-					var ranges []parser.Range
-					if baseType == types.BaseTypeOctetString {
-						ranges = syntax.SubType.OctetString
-						baseType = types.BaseTypeUnsigned32
-					} else {
-						ranges = syntax.SubType.Integer
-					}
-					rangeSort(ranges)
-					for _, r := range ranges {
-						if r.End == "" {
-							r.End = r.Start
-						}
-						currType.AddRange(GetValue(r.Start, baseType), GetValue(r.End, baseType))
-					}
-				} else if len(syntax.Enum) > 0 {
-					if baseType == types.BaseTypeEnum {
-						if parentType.List == nil || parentType.List.Ptr == nil {
-							// TODO: Figure out a better option. This should never happen.
-							baseType = types.BaseTypeInteger32
-						} else {
-							baseType = parentType.List.Ptr.(*NamedNumber).Value.BaseType
-						}
-					} else if baseType == types.BaseTypeBits {
-						baseType = types.BaseTypeUnsigned32
-					}
-					namedNumberSort(syntax.Enum)
-					for _, nn := range syntax.Enum {
-						currType.AddNamedNumber(nn.Name, GetValue(nn.Value, baseType))
-					}
-					if currType.BaseType == types.BaseTypeBits {
-						if parentType == smiHandle.TypeBits {
-							currType.Name = "Bits"
-						} else {
-							currType.Name = parentType.Name
-						}
-					} else {
-						if parentType.Module == nil || parentType.Module.IsWellKnown() {
-							currType.Name = "Enumeration"
-						} else {
-							currType.Name = parentType.Name
-						}
-						currType.BaseType = types.BaseTypeEnum
-					}
-				}
-				currObject.Type = currType
+				currObject.Type = out.getSyntaxType(*objType.Syntax.Type, currObject.Status)
 			}
 		case node.NotificationGroup != nil:
 			currObject.Decl = types.DeclNotificationGroup
@@ -674,7 +760,7 @@ func BuildModule(path string, in *parser.Module) (*Module, error) {
 			currObject.Status = node.ModuleCompliance.Status.ToSmi()
 			currObject.Description = node.ModuleCompliance.Description
 			currObject.Reference = node.ModuleCompliance.Reference
-			// TODO: Deal with node.ModuleCompliance.Modules
+			compliances = append(compliances, compliance{object: currObject, modules: node.ModuleCompliance.Modules})
 		case node.AgentCapabilities != nil:
 			currObject.Decl = types.DeclAgentCapabilities
 			currObject.NodeKind = types.NodeCapabilities
@@ -701,6 +787,9 @@ func BuildModule(path string, in *parser.Module) (*Module, error) {
 		}
 		out.Objects.AddWithOid(currObject, *node.Oid)
 	}
+	for _, c := range compliances {
+		out.addCompliance(c)
+	}
 	smiHandle.Modules.Add(out)
 	return out, nil
 }
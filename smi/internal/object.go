@@ -294,6 +294,22 @@ func FindObjectByModuleAndNode(modulePtr *Module, nodePtr *Node) *Object {
 	return nil
 }
 
+func FindImportedObjectByModuleAndNode(modulePtr *Module, nodePtr *Node) *Object {
+	if modulePtr == nil {
+		return nil
+	}
+	for obj := nodePtr.FirstObject; obj != nil; obj = obj.NextSameNode {
+		if obj.Module == nil {
+			continue
+		}
+		importPtr := modulePtr.Imports.Get(obj.Name)
+		if importPtr != nil && importPtr.Module == obj.Module.Name {
+			return obj
+		}
+	}
+	return nil
+}
+
 func FindObjectByModuleNameAndNode(module string, nodePtr *Node) *Object {
 	return FindObjectByModuleAndNode(FindModuleByName(module), nodePtr)
 }
@@ -2,6 +2,7 @@ package smi
 
 import (
 	"fmt"
+	"strings"
 	"unsafe"
 
 	"github.com/sleepinggenius2/gosmi/smi/internal"
@@ -27,6 +28,12 @@ func IsLoaded(module string) bool {
 	return internal.FindModuleByName(module) != nil
 }
 
+// IsWellKnownModule reports whether the module is the internal module that
+// holds the base types, rather than a loaded MIB module
+func IsWellKnownModule(smiModulePtr *types.SmiModule) bool {
+	return smiModulePtr != nil && smiModulePtr.Name == internal.WellKnownModuleName
+}
+
 // SmiModule *smiGetModule(const char *module)
 func GetModule(module string) *types.SmiModule {
 	if module == "" {
@@ -72,3 +79,22 @@ func GetModuleIdentityNode(smiModulePtr *types.SmiModule) *types.SmiNode {
 	}
 	return modulePtr.Identity.GetSmiNode()
 }
+
+// resolveQualifiedName splits a MODULE::name reference. If name is qualified,
+// the named module is returned, loading it if needed, in place of the given
+// module. ok is false if the qualifying module could not be found.
+func resolveQualifiedName(smiModulePtr *types.SmiModule, name string) (modulePtr *internal.Module, descriptor string, ok bool) {
+	i := strings.Index(name, "::")
+	if i < 0 {
+		if smiModulePtr != nil {
+			modulePtr = (*internal.Module)(unsafe.Pointer(smiModulePtr))
+		}
+		return modulePtr, name, true
+	}
+	descriptor = name[i+2:]
+	modulePtr, _ = internal.GetModule(name[:i])
+	if modulePtr == nil {
+		return nil, descriptor, false
+	}
+	return modulePtr, descriptor, true
+}
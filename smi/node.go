@@ -9,12 +9,11 @@ import (
 
 // SmiNode *smiGetNode(SmiModule *smiModulePtr, const char *name)
 func GetNode(smiModulePtr *types.SmiModule, name string) *types.SmiNode {
-	if name == "" {
+	modulePtr, name, ok := resolveQualifiedName(smiModulePtr, name)
+	if !ok || name == "" {
 		return nil
 	}
-	var modulePtr *internal.Module
-	if smiModulePtr != nil {
-		modulePtr = (*internal.Module)(unsafe.Pointer(smiModulePtr))
+	if modulePtr != nil {
 		objPtr := modulePtr.Objects.GetName(name)
 		if objPtr == nil {
 			return nil
@@ -131,7 +130,9 @@ func GetParentNode(smiNodePtr *types.SmiNode) *types.SmiNode {
 	var parentPtr *internal.Object
 	if objPtr.Module != nil {
 		parentPtr = internal.FindObjectByModuleAndNode(objPtr.Module, objPtr.Node.Parent)
-		if parentPtr != nil {
+		if parentPtr == nil {
+			parentPtr = internal.FindImportedObjectByModuleAndNode(objPtr.Module, objPtr.Node.Parent)
+		} else if parentPtr.Flags.Has(internal.FlagIncomplete) {
 			importPtr := objPtr.Module.Imports.Get(parentPtr.Name)
 			if importPtr != nil {
 				parentPtr = internal.FindObjectByModuleNameAndNode(string(importPtr.Module), objPtr.Node.Parent)
@@ -181,6 +182,20 @@ func GetFirstChildNode(smiNodePtr *types.SmiNode) *types.SmiNode {
 	return objPtr.GetSmiNode()
 }
 
+// GetFirstRootNode returns the first node directly under the root of the OID
+// tree, such as ccitt. Its siblings follow with GetNextChildNode.
+func GetFirstRootNode() *types.SmiNode {
+	rootPtr := internal.Root()
+	if rootPtr == nil || rootPtr.Children.First == nil {
+		return nil
+	}
+	objPtr := internal.FindObjectByNode(rootPtr.Children.First)
+	if objPtr == nil {
+		return nil
+	}
+	return objPtr.GetSmiNode()
+}
+
 // SmiNode *smiGetNextChildNode(SmiNode *smiNodePtr)
 func GetNextChildNode(smiNodePtr *types.SmiNode) *types.SmiNode {
 	if smiNodePtr == nil {
@@ -1,33 +1,42 @@
 package smi
 
 import (
+	"encoding/hex"
+	"net"
 	"strconv"
 	"strings"
 
+	"github.com/sleepinggenius2/gosmi/models"
 	"github.com/sleepinggenius2/gosmi/smi/internal"
 	"github.com/sleepinggenius2/gosmi/types"
 )
 
-func RenderNode(smiNodePtr *types.SmiNode, flags types.Render) string {
-	if smiNodePtr == nil {
-		if flags&types.RenderUnknown > 0 {
-			return internal.UnknownLabel
-		}
-		return ""
+func renderUnknown(flags types.Render) string {
+	if flags&types.RenderUnknown > 0 {
+		return internal.UnknownLabel
+	}
+	return ""
+}
+
+func qualifiedName(modulePtr *types.SmiModule, name types.SmiIdentifier, flags types.Render) string {
+	if flags&types.RenderQualified == 0 || modulePtr == nil || modulePtr.Name == "" || IsWellKnownModule(modulePtr) {
+		return name.String()
 	}
-	modulePtr := GetNodeModule(smiNodePtr)
-	if flags&types.RenderQualified == 0 || modulePtr == nil || modulePtr.Name == "" {
-		return smiNodePtr.Name.String()
+	return modulePtr.Name.String() + "::" + name.String()
+}
+
+// char *smiRenderNode(SmiNode *smiNodePtr, int flags)
+func RenderNode(smiNodePtr *types.SmiNode, flags types.Render) string {
+	if smiNodePtr == nil || smiNodePtr.Name == "" {
+		return renderUnknown(flags)
 	}
-	return modulePtr.Name.String() + "::" + smiNodePtr.Name.String()
+	return qualifiedName(GetNodeModule(smiNodePtr), smiNodePtr.Name, flags)
 }
 
+// char *smiRenderOID(unsigned int oidlen, SmiSubid *oid, int flags)
 func RenderOID(oid types.Oid, flags types.Render) string {
 	if len(oid) == 0 {
-		if flags&types.RenderUnknown > 0 {
-			return internal.UnknownLabel
-		}
-		return ""
+		return renderUnknown(flags)
 	}
 	var i int
 	var b strings.Builder
@@ -36,6 +45,12 @@ func RenderOID(oid types.Oid, flags types.Render) string {
 		if nodePtr != nil && nodePtr.Name != "" {
 			i = nodePtr.OidLen
 			b.WriteString(RenderNode(nodePtr, flags))
+			if flags&types.RenderFormat > 0 {
+				if instance, ok := renderInstance(nodePtr, oid[i:], flags); ok {
+					b.WriteString(instance)
+					return b.String()
+				}
+			}
 		}
 	}
 	for ; i < len(oid); i++ {
@@ -46,3 +61,313 @@ func RenderOID(oid types.Oid, flags types.Render) string {
 	}
 	return b.String()
 }
+
+func getIndexRow(smiNodePtr *types.SmiNode) *types.SmiNode {
+	if smiNodePtr.NodeKind != types.NodeColumn {
+		return nil
+	}
+	rowPtr := GetParentNode(smiNodePtr)
+	if rowPtr != nil && rowPtr.IndexKind == types.IndexAugment {
+		rowPtr = GetRelatedNode(rowPtr)
+	}
+	if rowPtr == nil || rowPtr.NodeKind != types.NodeRow || rowPtr.IndexKind != types.IndexIndex {
+		return nil
+	}
+	return rowPtr
+}
+
+// Builds the subset of the type needed to decode an index value, namely the
+// base type and any fixed size inherited from the type or its parents
+func getIndexType(smiTypePtr *types.SmiType) models.Type {
+	indexType := models.Type{BaseType: smiTypePtr.BaseType}
+	if smiTypePtr.BaseType != types.BaseTypeOctetString {
+		return indexType
+	}
+	for ; smiTypePtr != nil; smiTypePtr = GetParentType(smiTypePtr) {
+		rangePtr := GetMinMaxRange(smiTypePtr)
+		if rangePtr == nil {
+			continue
+		}
+		minValue, _ := rangePtr.MinValue.Value.(uint32)
+		maxValue, _ := rangePtr.MaxValue.Value.(uint32)
+		if minValue == maxValue {
+			indexType.Ranges = []models.Range{{BaseType: types.BaseTypeUnsigned32, MinValue: int64(minValue), MaxValue: int64(maxValue)}}
+		}
+		break
+	}
+	return indexType
+}
+
+func renderInstance(smiNodePtr *types.SmiNode, instance types.Oid, flags types.Render) (string, bool) {
+	if len(instance) == 0 {
+		return "", false
+	}
+	rowPtr := getIndexRow(smiNodePtr)
+	if rowPtr == nil {
+		return "", false
+	}
+	var indexTypePtrs []*types.SmiType
+	var indexTypes []models.Type
+	for elementPtr := GetFirstElement(rowPtr); elementPtr != nil; elementPtr = GetNextElement(elementPtr) {
+		indexPtr := GetElementNode(elementPtr)
+		if indexPtr == nil {
+			return "", false
+		}
+		indexTypePtr := GetNodeType(indexPtr)
+		if indexTypePtr == nil {
+			return "", false
+		}
+		indexTypePtrs = append(indexTypePtrs, indexTypePtr)
+		indexTypes = append(indexTypes, getIndexType(indexTypePtr))
+	}
+	values, lengths, err := models.DecodeInstance(indexTypes, instance, rowPtr.Implied)
+	if err != nil {
+		return "", false
+	}
+	var b strings.Builder
+	for i, value := range values {
+		indexTypePtr := indexTypePtrs[i]
+		b.WriteRune('.')
+		switch v := value.(type) {
+		case int64:
+			if indexTypePtr.BaseType == types.BaseTypeEnum {
+				b.WriteString(RenderValue(&types.SmiValue{BaseType: types.BaseTypeEnum, Value: v}, indexTypePtr, flags))
+			} else {
+				b.WriteString(strconv.FormatInt(v, 10))
+			}
+		case []byte:
+			if typeIsDerivedFrom(indexTypePtr, "IpAddress") && len(v) == net.IPv4len {
+				b.WriteString(net.IP(v).String())
+			} else if flags&types.RenderPrintable > 0 && models.IsPrintable(v) {
+				b.WriteString(strconv.Quote(string(v)))
+			} else {
+				b.WriteString(instance[:lengths[i]].String())
+			}
+		default:
+			b.WriteString(instance[:lengths[i]].String())
+		}
+		instance = instance[lengths[i]:]
+	}
+	return b.String(), true
+}
+
+func typeIsDerivedFrom(smiTypePtr *types.SmiType, name types.SmiIdentifier) bool {
+	for ; smiTypePtr != nil; smiTypePtr = GetParentType(smiTypePtr) {
+		if smiTypePtr.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func getTypeFormat(smiTypePtr *types.SmiType) string {
+	for ; smiTypePtr != nil; smiTypePtr = GetParentType(smiTypePtr) {
+		if smiTypePtr.Format != "" {
+			return smiTypePtr.Format
+		}
+	}
+	return ""
+}
+
+func valueToInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), v <= 1<<63-1
+	}
+	return 0, false
+}
+
+func valueToBytes(value interface{}) ([]byte, bool) {
+	switch v := value.(type) {
+	case []byte:
+		return v, true
+	case string:
+		return []byte(v), true
+	}
+	return nil, false
+}
+
+func renderInteger(smiValuePtr *types.SmiValue, smiTypePtr *types.SmiType, flags types.Render) string {
+	if v, ok := smiValuePtr.Value.(uint64); ok && v > 1<<63-1 {
+		return strconv.FormatUint(v, 10)
+	}
+	i, ok := valueToInt64(smiValuePtr.Value)
+	if !ok {
+		return renderUnknown(flags)
+	}
+	if flags&types.RenderFormat > 0 {
+		return models.IntegerDisplayHint(getTypeFormat(smiTypePtr), i)
+	}
+	return strconv.FormatInt(i, 10)
+}
+
+func renderEnum(smiValuePtr *types.SmiValue, smiTypePtr *types.SmiType, flags types.Render) string {
+	i, ok := valueToInt64(smiValuePtr.Value)
+	if !ok {
+		return renderUnknown(flags)
+	}
+	number := strconv.FormatInt(i, 10)
+	if flags&types.RenderName == 0 {
+		return number
+	}
+	for nn := GetFirstNamedNumber(smiTypePtr); nn != nil; nn = GetNextNamedNumber(nn) {
+		if v, ok := valueToInt64(nn.Value.Value); ok && v == i {
+			if flags&types.RenderNumeric > 0 {
+				return nn.Name.String() + "(" + number + ")"
+			}
+			return nn.Name.String()
+		}
+	}
+	return number
+}
+
+func renderBits(smiValuePtr *types.SmiValue, smiTypePtr *types.SmiType, flags types.Render) string {
+	octets, ok := valueToBytes(smiValuePtr.Value)
+	if !ok {
+		return renderUnknown(flags)
+	}
+	if flags&types.RenderName == 0 {
+		return "'" + strings.ToUpper(hex.EncodeToString(octets)) + "'H"
+	}
+	names := make([]string, 0)
+	for nn := GetFirstNamedNumber(smiTypePtr); nn != nil; nn = GetNextNamedNumber(nn) {
+		bit, ok := valueToInt64(nn.Value.Value)
+		if !ok || bit < 0 || bit/8 >= int64(len(octets)) {
+			continue
+		}
+		if octets[bit/8]&(0x80>>uint(bit%8)) != 0 {
+			names = append(names, nn.Name.String())
+		}
+	}
+	if len(names) == 0 {
+		return "{ }"
+	}
+	return "{ " + strings.Join(names, ", ") + " }"
+}
+
+func renderOctetString(smiValuePtr *types.SmiValue, smiTypePtr *types.SmiType, flags types.Render) string {
+	octets, ok := valueToBytes(smiValuePtr.Value)
+	if !ok {
+		return renderUnknown(flags)
+	}
+	if flags&types.RenderFormat > 0 {
+		format := getTypeFormat(smiTypePtr)
+		if format == "" && typeIsDerivedFrom(smiTypePtr, "IpAddress") {
+			format = "1d."
+		}
+		if format != "" {
+			return models.StringDisplayHint(format, octets)
+		}
+	}
+	if flags&types.RenderPrintable > 0 && models.IsPrintable(octets) {
+		return strconv.Quote(string(octets))
+	}
+	return "'" + strings.ToUpper(hex.EncodeToString(octets)) + "'H"
+}
+
+// char *smiRenderValue(SmiValue *smiValuePtr, SmiType *smiTypePtr, int flags)
+func RenderValue(smiValuePtr *types.SmiValue, smiTypePtr *types.SmiType, flags types.Render) string {
+	if smiValuePtr == nil || smiValuePtr.Value == nil {
+		return renderUnknown(flags)
+	}
+	switch smiValuePtr.BaseType {
+	case types.BaseTypeInteger32, types.BaseTypeInteger64, types.BaseTypeUnsigned32, types.BaseTypeUnsigned64:
+		return renderInteger(smiValuePtr, smiTypePtr, flags)
+	case types.BaseTypeFloat32, types.BaseTypeFloat64, types.BaseTypeFloat128:
+		switch v := smiValuePtr.Value.(type) {
+		case float32:
+			return strconv.FormatFloat(float64(v), 'G', -1, 32)
+		case float64:
+			return strconv.FormatFloat(v, 'G', -1, 64)
+		}
+	case types.BaseTypeEnum:
+		return renderEnum(smiValuePtr, smiTypePtr, flags)
+	case types.BaseTypeBits:
+		return renderBits(smiValuePtr, smiTypePtr, flags)
+	case types.BaseTypeOctetString:
+		return renderOctetString(smiValuePtr, smiTypePtr, flags)
+	case types.BaseTypeObjectIdentifier:
+		if oid, ok := smiValuePtr.Value.(types.Oid); ok {
+			return RenderOID(oid, flags)
+		}
+	}
+	return renderUnknown(flags)
+}
+
+func renderTypeName(smiTypePtr *types.SmiType, flags types.Render) string {
+	switch smiTypePtr.Name {
+	case "OctetString":
+		return "OCTET STRING"
+	case "ObjectIdentifier":
+		return "OBJECT IDENTIFIER"
+	case "Enum", "Enumeration":
+		return "INTEGER"
+	case "Bits":
+		return "BITS"
+	}
+	return qualifiedName(GetTypeModule(smiTypePtr), smiTypePtr.Name, flags)
+}
+
+func renderRangeValue(value types.SmiValue) string {
+	if v, ok := value.Value.(uint64); ok {
+		return strconv.FormatUint(v, 10)
+	}
+	i, _ := valueToInt64(value.Value)
+	return strconv.FormatInt(i, 10)
+}
+
+// char *smiRenderType(SmiType *smiTypePtr, int flags)
+//
+// Named types render as their name. Anonymous types, such as those used in
+// an OBJECT-TYPE SYNTAX clause, render as their parent type followed by
+// their own restrictions, e.g. Integer32 (0..100)
+func RenderType(smiTypePtr *types.SmiType, flags types.Render) string {
+	if smiTypePtr == nil {
+		return renderUnknown(flags)
+	}
+	parentPtr := smiTypePtr
+	if smiTypePtr.Decl == types.DeclImplicitType && GetParentType(smiTypePtr) != nil {
+		parentPtr = GetParentType(smiTypePtr)
+	} else if smiTypePtr.Name != "" {
+		return renderTypeName(smiTypePtr, flags)
+	}
+	name := renderTypeName(parentPtr, flags)
+	if smiTypePtr.BaseType == types.BaseTypeEnum && parentPtr.BaseType != types.BaseTypeEnum {
+		name = "INTEGER"
+	}
+
+	var restrictions []string
+	switch smiTypePtr.BaseType {
+	case types.BaseTypeEnum, types.BaseTypeBits:
+		for nn := GetFirstNamedNumber(smiTypePtr); nn != nil; nn = GetNextNamedNumber(nn) {
+			restrictions = append(restrictions, nn.Name.String()+"("+renderRangeValue(nn.Value)+")")
+		}
+		if len(restrictions) > 0 {
+			return name + " { " + strings.Join(restrictions, ", ") + " }"
+		}
+	default:
+		for r := GetFirstRange(smiTypePtr); r != nil; r = GetNextRange(r) {
+			minValue, maxValue := renderRangeValue(r.MinValue), renderRangeValue(r.MaxValue)
+			if minValue == maxValue {
+				restrictions = append(restrictions, minValue)
+			} else {
+				restrictions = append(restrictions, minValue+".."+maxValue)
+			}
+		}
+		if len(restrictions) > 0 {
+			if smiTypePtr.BaseType == types.BaseTypeOctetString {
+				return name + " (SIZE (" + strings.Join(restrictions, " | ") + "))"
+			}
+			return name + " (" + strings.Join(restrictions, " | ") + ")"
+		}
+	}
+	return name
+}
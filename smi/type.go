@@ -9,13 +9,12 @@ import (
 
 // SmiType *smiGetType(SmiModule *smiModulePtr, char *type)
 func GetType(smiModulePtr *types.SmiModule, typeName string) *types.SmiType {
-	if typeName == "" {
+	modulePtr, typeName, ok := resolveQualifiedName(smiModulePtr, typeName)
+	if !ok || typeName == "" {
 		return nil
 	}
 
-	var modulePtr *internal.Module
-	if smiModulePtr != nil {
-		modulePtr = (*internal.Module)(unsafe.Pointer(smiModulePtr))
+	if modulePtr != nil {
 		typePtr := modulePtr.Types.GetName(typeName)
 		if typePtr == nil {
 			return nil
@@ -57,10 +57,10 @@ func GetMinMaxRange(smiType *types.SmiType) *types.SmiRange {
 		rangePtr.MaxValue.Value = int64(math.MinInt64)
 	case types.BaseTypeUnsigned32:
 		rangePtr.MinValue.Value = uint32(math.MaxUint32)
-		rangePtr.MaxValue.Value = 0
+		rangePtr.MaxValue.Value = uint32(0)
 	case types.BaseTypeUnsigned64:
 		rangePtr.MinValue.Value = uint64(math.MaxUint64)
-		rangePtr.MaxValue.Value = 0
+		rangePtr.MaxValue.Value = uint64(0)
 	default:
 		return nil
 	}
@@ -81,10 +81,10 @@ func GetMinMaxRange(smiType *types.SmiType) *types.SmiRange {
 				rangePtr.MaxValue.Value = currRange.MaxValue.Value
 			}
 		case types.BaseTypeUnsigned32:
-			if currRange.MinValue.Value.(int32) < rangePtr.MinValue.Value.(int32) {
+			if currRange.MinValue.Value.(uint32) < rangePtr.MinValue.Value.(uint32) {
 				rangePtr.MinValue.Value = currRange.MinValue.Value
 			}
-			if currRange.MaxValue.Value.(int32) > rangePtr.MaxValue.Value.(int32) {
+			if currRange.MaxValue.Value.(uint32) > rangePtr.MaxValue.Value.(uint32) {
 				rangePtr.MaxValue.Value = currRange.MaxValue.Value
 			}
 		case types.BaseTypeUnsigned64:
@@ -0,0 +1,76 @@
+package smi
+
+import (
+	"unsafe"
+
+	"github.com/sleepinggenius2/gosmi/smi/internal"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+type WalkAction int
+
+const (
+	WalkContinue WalkAction = iota // Visit the children of the current node
+	WalkSkip                       // Skip the children of the current node
+	WalkStop                       // Stop the walk
+)
+
+type WalkFunc func(smiNodePtr *types.SmiNode) WalkAction
+
+func walkObject(nodePtr *internal.Node, modulePtrs []*internal.Module) *internal.Object {
+	if len(modulePtrs) == 0 {
+		return internal.FindObjectByNode(nodePtr)
+	}
+	for _, modulePtr := range modulePtrs {
+		if objPtr := internal.FindObjectByModuleAndNode(modulePtr, nodePtr); objPtr != nil {
+			return objPtr
+		}
+	}
+	return nil
+}
+
+func walkNode(nodePtr *internal.Node, modulePtrs []*internal.Module, fn WalkFunc) bool {
+	if objPtr := walkObject(nodePtr, modulePtrs); objPtr != nil {
+		switch fn(objPtr.GetSmiNode()) {
+		case WalkSkip:
+			return true
+		case WalkStop:
+			return false
+		}
+	}
+	for childPtr := nodePtr.Children.First; childPtr != nil; childPtr = childPtr.Next {
+		if !walkNode(childPtr, modulePtrs, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Walk visits the global OID tree in pre-order, starting at oid or at the
+// root if oid is empty, crossing module boundaries. If modules are given,
+// only objects defined in those modules are passed to fn, with the first
+// matching module taking precedence when several define the same OID. Nodes
+// without a matching object are not passed to fn, but their children are
+// still visited.
+func Walk(oid types.Oid, smiModulePtrs []*types.SmiModule, fn WalkFunc) {
+	nodePtr := internal.Root()
+	if nodePtr == nil || fn == nil {
+		return
+	}
+	if len(oid) > 0 {
+		nodePtr = internal.FindNodeByOid(len(oid), oid)
+		if nodePtr == nil {
+			return
+		}
+	}
+	modulePtrs := make([]*internal.Module, 0, len(smiModulePtrs))
+	for _, smiModulePtr := range smiModulePtrs {
+		if smiModulePtr != nil {
+			modulePtrs = append(modulePtrs, (*internal.Module)(unsafe.Pointer(smiModulePtr)))
+		}
+	}
+	if len(smiModulePtrs) > 0 && len(modulePtrs) == 0 {
+		return
+	}
+	walkNode(nodePtr, modulePtrs, fn)
+}
@@ -28,6 +28,11 @@ func (t SmiNode) getRow() (row *types.SmiNode) {
 	switch t.Kind {
 	case types.NodeRow:
 		row = t.GetRaw()
+	case types.NodeColumn:
+		row = smi.GetParentNode(t.smiNode)
+		if row == nil {
+			return
+		}
 	case types.NodeTable:
 		row = smi.GetFirstChildNode(t.smiNode)
 		if row == nil {
@@ -80,6 +85,13 @@ func (t SmiNode) GetImplied() bool {
 		return false
 	}
 
+	if row.IndexKind == types.IndexAugment {
+		row = smi.GetRelatedNode(row)
+		if row == nil {
+			return false
+		}
+	}
+
 	return row.Implied
 }
 
@@ -0,0 +1,253 @@
+package gosmi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+
+	"github.com/sleepinggenius2/gosmi/ber"
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// SnmpTrapsOid is the OID under which the SNMPv2 forms of the generic traps
+// are defined, as snmpTraps.1 for coldStart through snmpTraps.6 for
+// egpNeighborLoss
+var SnmpTrapsOid = types.OidMustFromString("1.3.6.1.6.3.1.1.5")
+
+// Instances of the objects used to translate between SNMPv1 and SNMPv2
+// notifications, as defined in RFC 3584 section 3
+var (
+	sysUpTimeInstance          = types.OidMustFromString("1.3.6.1.2.1.1.3.0")
+	snmpTrapOidInstance        = types.OidMustFromString("1.3.6.1.6.3.1.1.4.1.0")
+	snmpTrapEnterpriseInstance = types.OidMustFromString("1.3.6.1.6.3.1.1.4.3.0")
+	snmpTrapAddressInstance    = types.OidMustFromString("1.3.6.1.6.3.18.1.3.0")
+	snmpTrapCommunityInstance  = types.OidMustFromString("1.3.6.1.6.3.18.1.4.0")
+)
+
+// TrapOid returns the snmpTrapOID of an SNMPv1 trap, as defined in RFC 3584
+// section 3.1. The generic traps map to snmpTraps, so linkDown is
+// IF-MIB::linkDown, and enterprise-specific traps to the enterprise followed
+// by 0 and the specific trap, which is the OID of their TRAP-TYPE.
+func TrapOid(enterprise types.Oid, genericTrap GenericTrap, specificTrap int32) (types.Oid, error) {
+	switch {
+	case genericTrap >= GenericTrapColdStart && genericTrap < GenericTrapEnterpriseSpecific:
+		return types.NewOid(SnmpTrapsOid, types.SmiSubId(genericTrap)+1), nil
+	case genericTrap != GenericTrapEnterpriseSpecific:
+		return nil, fmt.Errorf("Invalid generic trap %d", int(genericTrap))
+	case specificTrap < 0:
+		return nil, fmt.Errorf("Invalid specific trap %d", specificTrap)
+	case len(enterprise) == 0:
+		return nil, errors.New("Enterprise-specific trap has no enterprise")
+	}
+	return types.NewOid(types.NewOid(enterprise, 0), types.SmiSubId(specificTrap)), nil
+}
+
+// TrapFields returns the enterprise, generic-trap and specific-trap of the
+// SNMPv1 form of a notification, as defined in RFC 3584 section 3.2. The
+// enterprise of a generic trap is the value of snmpTrapEnterprise.0, if given,
+// and otherwise snmpTraps. The enterprise of any other notification is its
+// OID without the last sub-identifier, and without the one before if it is 0.
+func TrapFields(trapOid types.Oid, snmpTrapEnterprise types.Oid) (enterprise types.Oid, genericTrap GenericTrap, specificTrap int32, err error) {
+	if len(trapOid) < 2 {
+		err = fmt.Errorf("Invalid notification OID %s", trapOid)
+		return
+	}
+	last := trapOid[len(trapOid)-1]
+	if trapOid.ChildOf(SnmpTrapsOid) && len(trapOid) == len(SnmpTrapsOid)+1 && last >= 1 && last <= 6 {
+		enterprise = snmpTrapEnterprise
+		if len(enterprise) == 0 {
+			enterprise = SnmpTrapsOid
+		}
+		return enterprise, GenericTrap(last - 1), 0, nil
+	}
+	if last > math.MaxInt32 {
+		err = fmt.Errorf("Notification OID %s cannot be an SNMPv1 trap", trapOid)
+		return
+	}
+	enterprise = trapOid[:len(trapOid)-1]
+	if enterprise[len(enterprise)-1] == 0 {
+		enterprise = enterprise[:len(enterprise)-1]
+	}
+	return enterprise, GenericTrapEnterpriseSpecific, int32(last), nil
+}
+
+// GetNotificationByOid returns the NOTIFICATION-TYPE or TRAP-TYPE with the
+// OID. Both have the same SNMPv2 form, since a TRAP-TYPE is placed under the
+// implicit zero sub-identifier of its enterprise.
+func GetNotificationByOid(trapOid types.Oid) (Notification, error) {
+	node, err := GetNodeByOID(trapOid)
+	if err != nil {
+		return Notification{}, err
+	}
+	if node.Kind != types.NodeNotification || !node.Oid.Equals(trapOid) {
+		return Notification{}, fmt.Errorf("Could not find notification for OID %s", trapOid)
+	}
+	return node.AsNotification(), nil
+}
+
+// GetTrapNotification returns the NOTIFICATION-TYPE or TRAP-TYPE of an SNMPv1
+// trap
+func GetTrapNotification(enterprise types.Oid, genericTrap GenericTrap, specificTrap int32) (Notification, error) {
+	trapOid, err := TrapOid(enterprise, genericTrap, specificTrap)
+	if err != nil {
+		return Notification{}, err
+	}
+	return GetNotificationByOid(trapOid)
+}
+
+// findVarbind returns the varbind with the OID, if any
+func findVarbind(varbinds []Varbind, oid types.Oid) (Varbind, bool) {
+	for _, vb := range varbinds {
+		if vb.Oid.Equals(oid) {
+			return vb, true
+		}
+	}
+	return Varbind{}, false
+}
+
+// NotificationOid returns the snmpTrapOID of an SNMPv1 Trap, or the value of
+// snmpTrapOID.0 for an SNMPv2-Trap or InformRequest
+func (pdu PDU) NotificationOid() (types.Oid, error) {
+	switch pdu.Type {
+	case PDUTrap:
+		return TrapOid(pdu.Enterprise, pdu.GenericTrap, pdu.SpecificTrap)
+	case PDUSNMPv2Trap, PDUInformRequest:
+		vb, ok := findVarbind(pdu.Varbinds, snmpTrapOidInstance)
+		if !ok {
+			return nil, fmt.Errorf("%s has no snmpTrapOID.0", pdu.Type)
+		}
+		oid, err := models.ToOid(vb.Value.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid snmpTrapOID.0: %w", err)
+		}
+		return oid, nil
+	}
+	return nil, fmt.Errorf("%s is not a notification", pdu.Type)
+}
+
+// Notification returns the NOTIFICATION-TYPE or TRAP-TYPE of the notification
+func (pdu PDU) Notification() (Notification, error) {
+	trapOid, err := pdu.NotificationOid()
+	if err != nil {
+		return Notification{}, err
+	}
+	return GetNotificationByOid(trapOid)
+}
+
+// newVarbind annotates a varbind created for a translated notification,
+// falling back to the type of the tag if its object is not loaded
+func newVarbind(oid types.Oid, tag ber.Tag, raw interface{}) Varbind {
+	vb, err := DecodeVarbind(oid, raw)
+	if err != nil {
+		vb = Varbind{Oid: oid, Err: err}
+	}
+	if vb.Node.Type == nil {
+		vb.Value = ber.TagType(tag).FormatValue(raw)
+	}
+	return vb
+}
+
+// ToSNMPv2Trap translates an SNMPv1 Trap into an SNMPv2-Trap, as defined in
+// RFC 3584 section 3.1. The varbinds are sysUpTime.0 and snmpTrapOID.0,
+// followed by those of the trap, snmpTrapAddress.0 with the agent-addr and
+// snmpTrapEnterprise.0 with the enterprise.
+func (pdu PDU) ToSNMPv2Trap() (PDU, error) {
+	if pdu.Type != PDUTrap {
+		return PDU{}, fmt.Errorf("%s is not an SNMPv1 Trap", pdu.Type)
+	}
+	trapOid, err := pdu.NotificationOid()
+	if err != nil {
+		return PDU{}, err
+	}
+	agentAddr := pdu.AgentAddr.To4()
+	if agentAddr == nil {
+		agentAddr = net.IPv4zero.To4()
+	}
+	varbinds := make([]Varbind, 0, len(pdu.Varbinds)+4)
+	varbinds = append(varbinds,
+		newVarbind(sysUpTimeInstance, ber.TagTimeTicks, int64(pdu.Timestamp)),
+		newVarbind(snmpTrapOidInstance, ber.TagObjectIdentifier, trapOid),
+	)
+	varbinds = append(varbinds, pdu.Varbinds...)
+	varbinds = append(varbinds,
+		newVarbind(snmpTrapAddressInstance, ber.TagIpAddress, []byte(agentAddr)),
+		newVarbind(snmpTrapEnterpriseInstance, ber.TagObjectIdentifier, pdu.Enterprise),
+	)
+	return PDU{Type: PDUSNMPv2Trap, Varbinds: varbinds}, nil
+}
+
+// ToTrap translates an SNMPv2-Trap or InformRequest into an SNMPv1 Trap, as
+// defined in RFC 3584 section 3.2. The agent-addr is the value of
+// snmpTrapAddress.0, or 0.0.0.0 if it is not given. The varbinds used for the
+// fields of the trap are removed, as are Counter64 varbinds, which SNMPv1
+// cannot carry.
+func (pdu PDU) ToTrap() (PDU, error) {
+	if pdu.Type != PDUSNMPv2Trap && pdu.Type != PDUInformRequest {
+		return PDU{}, fmt.Errorf("%s is not an SNMPv2 notification", pdu.Type)
+	}
+	trapOid, err := pdu.NotificationOid()
+	if err != nil {
+		return PDU{}, err
+	}
+	trap := PDU{Type: PDUTrap, AgentAddr: net.IPv4zero.To4()}
+	var snmpTrapEnterprise types.Oid
+	for _, vb := range pdu.Varbinds {
+		switch {
+		case vb.Oid.Equals(sysUpTimeInstance):
+			i, ticks, unsigned, err := rawInteger(vb.Value.Raw)
+			if !unsigned {
+				ticks = uint64(i)
+			}
+			if err != nil || i < 0 || ticks > math.MaxUint32 {
+				return PDU{}, fmt.Errorf("Invalid sysUpTime.0 %v", vb.Value.Raw)
+			}
+			trap.Timestamp = uint32(ticks)
+		case vb.Oid.Equals(snmpTrapOidInstance), vb.Oid.Equals(snmpTrapCommunityInstance):
+		case vb.Oid.Equals(snmpTrapEnterpriseInstance):
+			if snmpTrapEnterprise, err = models.ToOid(vb.Value.Raw); err != nil {
+				return PDU{}, fmt.Errorf("Invalid snmpTrapEnterprise.0: %w", err)
+			}
+		case vb.Oid.Equals(snmpTrapAddressInstance):
+			octets, ok := vb.Value.Raw.([]byte)
+			if !ok || len(octets) != net.IPv4len {
+				return PDU{}, fmt.Errorf("Invalid snmpTrapAddress.0 %v", vb.Value.Raw)
+			}
+			trap.AgentAddr = net.IP(octets)
+		case vb.Value.BaseType == types.BaseTypeUnsigned64:
+		default:
+			trap.Varbinds = append(trap.Varbinds, vb)
+		}
+	}
+	trap.Enterprise, trap.GenericTrap, trap.SpecificTrap, err = TrapFields(trapOid, snmpTrapEnterprise)
+	if err != nil {
+		return PDU{}, err
+	}
+	return trap, nil
+}
+
+// ToSNMPv2c translates a message with an SNMPv1 Trap into an SNMPv2c message
+// with an SNMPv2-Trap, as a proxy forwarder does, appending
+// snmpTrapCommunity.0 with the community of the message
+func (m Message) ToSNMPv2c() (Message, error) {
+	pdu, err := m.PDU.ToSNMPv2Trap()
+	if err != nil {
+		return Message{}, err
+	}
+	community := newVarbind(snmpTrapCommunityInstance, ber.TagOctetString, []byte(m.Community))
+	last := len(pdu.Varbinds) - 1
+	enterprise := pdu.Varbinds[last]
+	pdu.Varbinds = append(pdu.Varbinds[:last], community, enterprise)
+	return Message{Version: SnmpVersion2c, Community: m.Community, PDU: pdu}, nil
+}
+
+// ToSNMPv1 translates a message with an SNMPv2-Trap or InformRequest into an
+// SNMPv1 message with a Trap
+func (m Message) ToSNMPv1() (Message, error) {
+	pdu, err := m.PDU.ToTrap()
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{Version: SnmpVersion1, Community: m.Community, PDU: pdu}, nil
+}
@@ -0,0 +1,232 @@
+package gosmi_test
+
+import (
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+const (
+	netSnmpAgentOid = "1.3.6.1.4.1.8072.3.2.10"
+	testV1Oid       = "1.3.6.1.4.1.88888"
+	linkDownOid     = "1.3.6.1.6.3.1.1.5.3"
+
+	snmpTrapOidInstance        = "1.3.6.1.6.3.1.1.4.1.0"
+	snmpTrapEnterpriseInstance = "1.3.6.1.6.3.1.1.4.3.0"
+	snmpTrapAddressInstance    = "1.3.6.1.6.3.18.1.3.0"
+	snmpTrapCommunityInstance  = "1.3.6.1.6.3.18.1.4.0"
+)
+
+func TestTrapOid(t *testing.T) {
+	tests := []struct {
+		source       string
+		enterprise   string
+		genericTrap  gosmi.GenericTrap
+		specificTrap int32
+		oid          string
+		notification string
+	}{
+		{"Generic", netSnmpAgentOid, gosmi.GenericTrapLinkDown, 0, linkDownOid, "IF-MIB::linkDown"},
+		{"Generic ignoring specific", testV1Oid, gosmi.GenericTrapLinkUp, 5, "1.3.6.1.6.3.1.1.5.4", "IF-MIB::linkUp"},
+		{"Enterprise-specific", testV1Oid, gosmi.GenericTrapEnterpriseSpecific, 7, testV1Oid + ".0.7", "TEST-V1-MIB::testV1Alarm"},
+		{"Invalid generic", testV1Oid, gosmi.GenericTrap(7), 0, "", ""},
+		{"Invalid specific", testV1Oid, gosmi.GenericTrapEnterpriseSpecific, -1, "", ""},
+		{"No enterprise", "", gosmi.GenericTrapEnterpriseSpecific, 7, "", ""},
+	}
+	for _, test := range tests {
+		var enterprise types.Oid
+		if test.enterprise != "" {
+			enterprise = types.OidMustFromString(test.enterprise)
+		}
+		oid, err := gosmi.TrapOid(enterprise, test.genericTrap, test.specificTrap)
+		if test.oid == "" {
+			if err == nil {
+				t.Errorf("%s: TrapOid = %s, want error", test.source, oid)
+			}
+			continue
+		}
+		if err != nil || oid.String() != test.oid {
+			t.Errorf("%s: TrapOid = %s, %v, want %s", test.source, oid, err, test.oid)
+			continue
+		}
+		notification, err := gosmi.GetTrapNotification(enterprise, test.genericTrap, test.specificTrap)
+		if name := notification.GetModule().Name + "::" + notification.Name; err != nil || name != test.notification {
+			t.Errorf("%s: GetTrapNotification = %s, %v, want %s", test.source, name, err, test.notification)
+		}
+	}
+}
+
+func TestTrapFields(t *testing.T) {
+	tests := []struct {
+		source             string
+		oid                string
+		snmpTrapEnterprise string
+		enterprise         string
+		genericTrap        gosmi.GenericTrap
+		specificTrap       int32
+	}{
+		{"Generic", linkDownOid, "", "1.3.6.1.6.3.1.1.5", gosmi.GenericTrapLinkDown, 0},
+		{"Generic with snmpTrapEnterprise", linkDownOid, netSnmpAgentOid, netSnmpAgentOid, gosmi.GenericTrapLinkDown, 0},
+		{"Enterprise-specific with 0", testV1Oid + ".0.7", "", testV1Oid, gosmi.GenericTrapEnterpriseSpecific, 7},
+		{"Enterprise-specific without 0", testV1Oid + ".7", "", testV1Oid, gosmi.GenericTrapEnterpriseSpecific, 7},
+		{"Enterprise-specific ignoring snmpTrapEnterprise", testV1Oid + ".0.7", netSnmpAgentOid, testV1Oid, gosmi.GenericTrapEnterpriseSpecific, 7},
+		{"Under snmpTraps", "1.3.6.1.6.3.1.1.5.7", "", "1.3.6.1.6.3.1.1.5", gosmi.GenericTrapEnterpriseSpecific, 7},
+		{"Too short", "1", "", "", 0, 0},
+		{"Specific trap overflow", testV1Oid + ".0.2147483648", "", "", 0, 0},
+	}
+	for _, test := range tests {
+		var snmpTrapEnterprise types.Oid
+		if test.snmpTrapEnterprise != "" {
+			snmpTrapEnterprise = types.OidMustFromString(test.snmpTrapEnterprise)
+		}
+		enterprise, genericTrap, specificTrap, err := gosmi.TrapFields(types.OidMustFromString(test.oid), snmpTrapEnterprise)
+		if test.enterprise == "" {
+			if err == nil {
+				t.Errorf("%s: TrapFields = %s %s/%d, want error", test.source, enterprise, genericTrap, specificTrap)
+			}
+			continue
+		}
+		if err != nil || enterprise.String() != test.enterprise || genericTrap != test.genericTrap || specificTrap != test.specificTrap {
+			t.Errorf("%s: TrapFields = %s %s/%d, %v, want %s %s/%d", test.source, enterprise, genericTrap, specificTrap, err, test.enterprise, test.genericTrap, test.specificTrap)
+		}
+	}
+}
+
+func varbindOids(varbinds []gosmi.Varbind) []string {
+	oids := make([]string, len(varbinds))
+	for i, vb := range varbinds {
+		oids[i] = vb.Oid.String()
+	}
+	return oids
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTrapRoundTrip(t *testing.T) {
+	v1, err := gosmi.DecodeMessage(decodePayload(t, v1TrapPayload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2, err := v1.ToSNMPv2c()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v2.Version != gosmi.SnmpVersion2c || v2.Community != "public" || v2.PDU.Type != gosmi.PDUSNMPv2Trap {
+		t.Errorf("ToSNMPv2c = %s %q %s, want SNMPv2c \"public\" SNMPv2-Trap", v2.Version, v2.Community, v2.PDU.Type)
+	}
+	wantOids := []string{
+		"1.3.6.1.2.1.1.3.0",
+		snmpTrapOidInstance,
+		"1.3.6.1.2.1.2.2.1.1.3",
+		"1.3.6.1.2.1.2.2.1.7.3",
+		"1.3.6.1.2.1.2.2.1.8.3",
+		snmpTrapAddressInstance,
+		snmpTrapCommunityInstance,
+		snmpTrapEnterpriseInstance,
+	}
+	varbinds := v2.PDU.Varbinds
+	if oids := varbindOids(varbinds); !equalStrings(oids, wantOids) {
+		t.Fatalf("ToSNMPv2c varbinds = %v, want %v", oids, wantOids)
+	}
+	wantStrings := map[int]string{
+		0: "SNMPv2-MIB::sysUpTime.0 = 2m 3s",
+		1: "SNMPv2-MIB::snmpTrapOID.0 = " + linkDownOid,
+		7: "SNMPv2-MIB::snmpTrapEnterprise.0 = " + netSnmpAgentOid,
+	}
+	for i, want := range wantStrings {
+		if s := varbinds[i].String(); s != want {
+			t.Errorf("ToSNMPv2c varbind %d = %q, want %q", i+1, s, want)
+		}
+	}
+	if addr, _ := varbinds[5].Value.Raw.([]byte); len(addr) != 4 || addr[0] != 192 || addr[3] != 1 {
+		t.Errorf("snmpTrapAddress.0 = %v, want 192.0.2.1", varbinds[5].Value.Raw)
+	}
+	if community, _ := varbinds[6].Value.Raw.([]byte); string(community) != "public" {
+		t.Errorf("snmpTrapCommunity.0 = %v, want public", varbinds[6].Value.Raw)
+	}
+	if notification, err := v2.PDU.Notification(); err != nil || notification.Name != "linkDown" {
+		t.Errorf("Notification = %s, %v, want linkDown", notification.Name, err)
+	}
+
+	back, err := v2.ToSNMPv1()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdu := back.PDU
+	if back.Version != gosmi.SnmpVersion1 || back.Community != "public" || pdu.Type != gosmi.PDUTrap {
+		t.Errorf("ToSNMPv1 = %s %q %s, want SNMPv1 \"public\" Trap", back.Version, back.Community, pdu.Type)
+	}
+	if pdu.Enterprise.String() != netSnmpAgentOid || pdu.AgentAddr.String() != "192.0.2.1" {
+		t.Errorf("ToSNMPv1 enterprise %s from %s, want %s from 192.0.2.1", pdu.Enterprise, pdu.AgentAddr, netSnmpAgentOid)
+	}
+	if pdu.GenericTrap != gosmi.GenericTrapLinkDown || pdu.SpecificTrap != 0 || pdu.Timestamp != 12345 {
+		t.Errorf("ToSNMPv1 trap is %s/%d at %d, want linkDown/0 at 12345", pdu.GenericTrap, pdu.SpecificTrap, pdu.Timestamp)
+	}
+	if oids := varbindOids(pdu.Varbinds); !equalStrings(oids, wantOids[2:5]) {
+		t.Errorf("ToSNMPv1 varbinds = %v, want %v", oids, wantOids[2:5])
+	}
+
+	// Without snmpTrapEnterprise.0, the enterprise of a generic trap is
+	// snmpTraps
+	v2.PDU.Varbinds = varbinds[:len(varbinds)-1]
+	if back, err = v2.ToSNMPv1(); err != nil || back.PDU.Enterprise.String() != "1.3.6.1.6.3.1.1.5" {
+		t.Errorf("ToSNMPv1 without snmpTrapEnterprise.0: enterprise %s, %v, want snmpTraps", back.PDU.Enterprise, err)
+	}
+}
+
+func TestToTrapEnterpriseSpecific(t *testing.T) {
+	for _, trapOid := range []string{testV1Oid + ".0.7", testV1Oid + ".7"} {
+		var varbinds []gosmi.Varbind
+		for _, value := range []struct {
+			oid string
+			raw interface{}
+		}{
+			{"1.3.6.1.2.1.1.3.0", 100},
+			{snmpTrapOidInstance, types.OidMustFromString(trapOid)},
+			{"1.3.6.1.2.1.31.1.1.1.6.3", uint64(1 << 40)},
+			{testV1Oid + ".1.0", []byte("Alarm")},
+		} {
+			vb, err := gosmi.DecodeVarbind(types.OidMustFromString(value.oid), value.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			varbinds = append(varbinds, vb)
+		}
+		trap, err := gosmi.PDU{Type: gosmi.PDUSNMPv2Trap, Varbinds: varbinds}.ToTrap()
+		if err != nil {
+			t.Errorf("%s: ToTrap: %v", trapOid, err)
+			continue
+		}
+		if trap.Enterprise.String() != testV1Oid || trap.GenericTrap != gosmi.GenericTrapEnterpriseSpecific || trap.SpecificTrap != 7 {
+			t.Errorf("%s: ToTrap = %s %s/%d, want %s enterpriseSpecific/7", trapOid, trap.Enterprise, trap.GenericTrap, trap.SpecificTrap, testV1Oid)
+		}
+		if trap.AgentAddr.String() != "0.0.0.0" || trap.Timestamp != 100 {
+			t.Errorf("%s: ToTrap from %s at %d, want 0.0.0.0 at 100", trapOid, trap.AgentAddr, trap.Timestamp)
+		}
+		// The Counter64 ifHCInOctets cannot be carried by SNMPv1
+		if oids := varbindOids(trap.Varbinds); !equalStrings(oids, []string{testV1Oid + ".1.0"}) {
+			t.Errorf("%s: ToTrap varbinds = %v, want only testV1Message.0", trapOid, oids)
+		}
+
+		// The TRAP-TYPE is found from the trap fields, which always have the 0
+		v2, err := trap.ToSNMPv2Trap()
+		if err != nil {
+			t.Errorf("%s: ToSNMPv2Trap: %v", trapOid, err)
+			continue
+		}
+		if notification, err := v2.Notification(); err != nil || notification.Name != "testV1Alarm" {
+			t.Errorf("%s: Notification = %s, %v, want testV1Alarm", trapOid, notification.Name, err)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package gosmi
 
 import (
 	"fmt"
+	"math"
+	"sort"
 
 	"github.com/sleepinggenius2/gosmi/models"
 	"github.com/sleepinggenius2/gosmi/smi"
@@ -27,9 +29,11 @@ func (t *SmiType) getEnum() {
 		BaseType: types.BaseType(smiNamedNumber.Value.BaseType),
 	}
 	for ; smiNamedNumber != nil; smiNamedNumber = smi.GetNextNamedNumber(smiNamedNumber) {
+		// Named numbers are limited to Integer32 by the SMI
+		value, _ := convertValue(smiNamedNumber.Value)
 		namedNumber := models.NamedNumber{
 			Name:  string(smiNamedNumber.Name),
-			Value: convertValue(smiNamedNumber.Value),
+			Value: value,
 		}
 		enum.Values = append(enum.Values, namedNumber)
 	}
@@ -51,17 +55,140 @@ func (t *SmiType) getRanges() {
 	// Workaround for libsmi bug that causes ranges to loop infinitely sometimes
 	var currSmiRange *types.SmiRange
 	for smiRange := smi.GetFirstRange(t.smiType); smiRange != nil && smiRange != currSmiRange; smiRange = smi.GetNextRange(smiRange) {
-		r := models.Range{
-			BaseType: smiRange.MinValue.BaseType,
-			MinValue: convertValue(smiRange.MinValue),
-			MaxValue: convertValue(smiRange.MaxValue),
-		}
+		r := models.Range{BaseType: smiRange.MinValue.BaseType}
+		r.MinValue, r.MinUnsigned = convertValue(smiRange.MinValue)
+		r.MaxValue, r.MaxUnsigned = convertValue(smiRange.MaxValue)
 		ranges = append(ranges, r)
 		currSmiRange = smiRange
 	}
 	t.Ranges = ranges
 }
 
+// GetDerivationChain returns the chain of types that this type is derived
+// from, starting with the type itself and ending with the base type. Implicit
+// refinements, such as DisplayString (SIZE (0..32)), are kept as separate
+// unnamed entries.
+func (t SmiType) GetDerivationChain() (chain []SmiType) {
+	for smiType := t.smiType; smiType != nil; smiType = smi.GetParentType(smiType) {
+		chainType := CreateType(smiType)
+		if smiType.Name == "" {
+			chainType.Decl = smiType.Decl
+			chainType.Description = smiType.Description
+			chainType.DerivedFrom = typeIdentities(smiType)
+			chainType.Format = smiType.Format
+			chainType.Module = ""
+			chainType.Name = ""
+			chainType.Reference = smiType.Reference
+			chainType.Status = smiType.Status
+			chainType.Units = smiType.Units
+		}
+		chain = append(chain, chainType)
+	}
+	return
+}
+
+// typeIdentities returns the module-qualified identities of the named types
+// that the type is derived from, nearest first. The base types of the
+// well-known module are left out, as they are given by the BaseType.
+func typeIdentities(smiType *types.SmiType) (identities []string) {
+	for smiType = smi.GetParentType(smiType); smiType != nil; smiType = smi.GetParentType(smiType) {
+		if smiType.Name == "" {
+			continue
+		}
+		smiModule := smi.GetTypeModule(smiType)
+		if smi.IsWellKnownModule(smiModule) {
+			continue
+		}
+		identity := string(smiType.Name)
+		if smiModule != nil && smiModule.Name != "" {
+			identity = string(smiModule.Name) + "::" + identity
+		}
+		identities = append(identities, identity)
+	}
+	return
+}
+
+// minLess reports whether the lower bound of range a is below that of b
+func minLess(a models.Range, b models.Range) bool {
+	if a.BaseType == types.BaseTypeUnsigned64 {
+		return a.MinUnsigned < b.MinUnsigned
+	}
+	return a.MinValue < b.MinValue
+}
+
+// maxLess reports whether the upper bound of range a is below that of b
+func maxLess(a models.Range, b models.Range) bool {
+	if a.BaseType == types.BaseTypeUnsigned64 {
+		return a.MaxUnsigned < b.MaxUnsigned
+	}
+	return a.MaxValue < b.MaxValue
+}
+
+func intersectRanges(a []models.Range, b []models.Range) (ranges []models.Range) {
+	if len(a) == 0 {
+		return b
+	}
+	if len(b) == 0 {
+		return a
+	}
+	ranges = make([]models.Range, 0)
+	for _, ra := range a {
+		for _, rb := range b {
+			r := ra
+			if minLess(r, rb) {
+				r.MinValue, r.MinUnsigned = rb.MinValue, rb.MinUnsigned
+			}
+			if maxLess(rb, r) {
+				r.MaxValue, r.MaxUnsigned = rb.MaxValue, rb.MaxUnsigned
+			}
+			empty := r.MaxValue < r.MinValue
+			if r.BaseType == types.BaseTypeUnsigned64 {
+				empty = r.MaxUnsigned < r.MinUnsigned
+			}
+			if !empty {
+				ranges = append(ranges, r)
+			}
+		}
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return minLess(ranges[i], ranges[j])
+	})
+	return
+}
+
+// GetEffectiveType returns the type with the constraints that are actually in
+// force after walking the derivation chain: the intersection of all ranges,
+// the nearest DISPLAY-HINT and units, and the nearest enumeration or bits.
+func (t SmiType) GetEffectiveType() (outType models.Type) {
+	outType = t.Type
+	outType.Enum = nil
+	outType.Ranges = nil
+	for i, chainType := range t.GetDerivationChain() {
+		if i > 0 && outType.Name == "" {
+			outType.Module = chainType.Module
+			outType.Name = chainType.Name
+		}
+		if outType.Format == "" {
+			outType.Format = chainType.Format
+		}
+		if outType.Units == "" {
+			outType.Units = chainType.Units
+		}
+		if outType.Enum == nil && chainType.Enum != nil {
+			outType.Enum = chainType.Enum
+		}
+		outType.Ranges = intersectRanges(outType.Ranges, chainType.Ranges)
+	}
+	if outType.Ranges == nil {
+		outType.Ranges = make([]models.Range, 0)
+	}
+	return
+}
+
+func (t SmiType) Render(flags types.Render) string {
+	return smi.RenderType(t.smiType, flags)
+}
+
 func (t SmiType) String() string {
 	return t.Type.String()
 }
@@ -87,8 +214,12 @@ func CreateType(smiType *types.SmiType) (outType SmiType) {
 	}
 
 	outType.Decl = smiType.Decl
+	outType.DerivedFrom = typeIdentities(smiType)
 	outType.Description = smiType.Description
 	outType.Format = smiType.Format
+	if smiModule := smi.GetTypeModule(smiType); smiModule != nil {
+		outType.Module = string(smiModule.Name)
+	}
 	outType.Name = string(smiType.Name)
 	outType.Reference = smiType.Reference
 	outType.Status = smiType.Status
@@ -138,16 +269,25 @@ func GetType(name string, module ...SmiModule) (outType SmiType, err error) {
 	return CreateType(smiType), nil
 }
 
-func convertValue(value types.SmiValue) (outValue int64) {
+// convertValue converts an integer value to both int64 and uint64, each
+// clamped to its range, so that a uint64 above math.MaxInt64 is not mistaken
+// for a negative number
+func convertValue(value types.SmiValue) (signed int64, unsigned uint64) {
 	switch v := value.Value.(type) {
 	case int32:
-		outValue = int64(v)
+		signed = int64(v)
 	case int64:
-		outValue = int64(v)
+		signed = v
 	case uint32:
-		outValue = int64(v)
+		signed = int64(v)
 	case uint64:
-		outValue = int64(v)
+		if v > math.MaxInt64 {
+			return math.MaxInt64, v
+		}
+		signed = int64(v)
+	}
+	if signed > 0 {
+		unsigned = uint64(signed)
 	}
 	return
 }
@@ -0,0 +1,94 @@
+package gosmi_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/models"
+)
+
+func TestUnsigned64Ranges(t *testing.T) {
+	node, err := gosmi.GetNode("testBigGauge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	effective, err := node.GetEffectiveType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(effective.Ranges) != 1 {
+		t.Fatalf("Got ranges %v, want 10..18446744073709551000", effective.Ranges)
+	}
+	r := effective.Ranges[0]
+	if r.MinUnsigned != 10 || r.MaxUnsigned != 18446744073709551000 || r.String() != "10..18446744073709551000" {
+		t.Errorf("Got range %s with bounds %d and %d, want 10..18446744073709551000", r, r.MinUnsigned, r.MaxUnsigned)
+	}
+	for _, value := range []interface{}{uint64(10), uint64(1 << 63), uint64(18446744073709551000)} {
+		if err := node.Validate(value); err != nil {
+			t.Errorf("Validate(%d): %v", value, err)
+		}
+	}
+	for _, value := range []interface{}{uint64(9), uint64(18446744073709551001)} {
+		if err := node.Validate(value); !errors.Is(err, models.ErrOutOfRange) {
+			t.Errorf("Validate(%d) error = %v, want ErrOutOfRange", value, err)
+		}
+	}
+	if err := node.Validate(-1); !errors.Is(err, models.ErrInvalidType) {
+		t.Errorf("Validate(-1) error = %v, want ErrInvalidType", err)
+	}
+}
+
+func TestParseValueFormatted(t *testing.T) {
+	tests := []struct {
+		node string
+		raw  interface{}
+	}{
+		{"sysUpTime", int64(8640000)},
+		{"sysUpTime", int64(4500)},
+		{"testEntryPeer", []byte{0xfe, 0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 4}},
+		{"testEntryPeer", []byte{192, 0, 2, 1}},
+		{"ifAdminStatus", int64(2)},
+	}
+	for _, test := range tests {
+		node := getNode(t, test.node)
+		formatted := node.FormatValue(test.raw).Formatted
+		if parsed, err := node.ParseValue(formatted); err != nil || !reflect.DeepEqual(parsed, test.raw) {
+			t.Errorf("%s: ParseValue(%q) = %#v, %v, want %#v", test.node, formatted, parsed, err, test.raw)
+		}
+	}
+	// The dns form of testEntryPeer is only formatted when paired with
+	// testEntryPeerType, but parses back to its octets alone
+	if parsed, err := getNode(t, "testEntryPeer").ParseValue("peer.net"); err != nil || !reflect.DeepEqual(parsed, []byte("peer.net")) {
+		t.Errorf("testEntryPeer: ParseValue(\"peer.net\") = %#v, %v", parsed, err)
+	}
+}
+
+func TestGetDerivationChain(t *testing.T) {
+	// The base types of the well-known module end each chain, but are not
+	// among the identities that any type is derived from
+	tests := []struct {
+		node        string
+		names       []string
+		derivedFrom [][]string
+	}{
+		{"sysDescr", []string{"", "DisplayString", "OctetString"}, [][]string{{"SNMPv2-TC::DisplayString"}, nil, nil}},
+		{"testEntryValue", []string{"", "Unsigned32", "Unsigned32"}, [][]string{{"SNMPv2-SMI::Unsigned32"}, nil, nil}},
+		{"sysUpTime", []string{"TimeTicks", "Unsigned32"}, [][]string{nil, nil}},
+		{"testEntryPeer", []string{"InetAddress", "OctetString"}, [][]string{nil, nil}},
+	}
+	for _, test := range tests {
+		node := getNode(t, test.node)
+		chain := node.SmiType.GetDerivationChain()
+		names := make([]string, len(chain))
+		derivedFrom := make([][]string, len(chain))
+		for i, chainType := range chain {
+			names[i] = chainType.Name
+			derivedFrom[i] = chainType.DerivedFrom
+		}
+		if !reflect.DeepEqual(names, test.names) || !reflect.DeepEqual(derivedFrom, test.derivedFrom) {
+			t.Errorf("%s: GetDerivationChain = %q derived from %q, want %q derived from %q", test.node, names, derivedFrom, test.names, test.derivedFrom)
+		}
+	}
+}
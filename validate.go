@@ -0,0 +1,89 @@
+package gosmi
+
+import (
+	"fmt"
+
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/smi"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// getRefinementType returns the type that the compliance statement refines
+// this node to, preferring the WRITE-SYNTAX if write is set
+func (n SmiNode) getRefinementType(compliance SmiNode, write bool) *types.SmiType {
+	for smiRefinement := smi.GetFirstRefinement(compliance.smiNode); smiRefinement != nil; smiRefinement = smi.GetNextRefinement(smiRefinement) {
+		smiNode := smi.GetRefinementNode(smiRefinement)
+		if smiNode == nil || (smiNode != n.smiNode && !smiNode.Oid.Equals(n.Oid)) {
+			continue
+		}
+		if write {
+			if smiType := smi.GetRefinementWriteType(smiRefinement); smiType != nil {
+				return smiType
+			}
+		}
+		return smi.GetRefinementType(smiRefinement)
+	}
+	return nil
+}
+
+func (n SmiNode) getEffectiveType(write bool, compliance []SmiNode) (outType models.Type, err error) {
+	if n.SmiType == nil {
+		err = fmt.Errorf("Node %s has no type", n.Name)
+		return
+	}
+	outType = n.SmiType.GetEffectiveType()
+	for _, c := range compliance {
+		smiType := n.getRefinementType(c, write)
+		if smiType == nil {
+			continue
+		}
+		refinedType := CreateType(smiType).GetEffectiveType()
+		outType.Ranges = intersectRanges(outType.Ranges, refinedType.Ranges)
+		if refinedType.Enum != nil {
+			outType.Enum = refinedType.Enum
+		}
+	}
+	return
+}
+
+// GetEffectiveType returns the effective type of the node, with the SYNTAX
+// refinements of the given MODULE-COMPLIANCE nodes applied
+func (n SmiNode) GetEffectiveType(compliance ...SmiNode) (models.Type, error) {
+	return n.getEffectiveType(false, compliance)
+}
+
+// GetEffectiveWriteType returns the effective type of the node for a SET,
+// with the WRITE-SYNTAX, or otherwise SYNTAX, refinements of the given
+// MODULE-COMPLIANCE nodes applied
+func (n SmiNode) GetEffectiveWriteType(compliance ...SmiNode) (models.Type, error) {
+	return n.getEffectiveType(true, compliance)
+}
+
+// Validate checks the value against the effective type of the node, with the
+// SYNTAX refinements of the given MODULE-COMPLIANCE nodes applied
+func (n SmiNode) Validate(value interface{}, compliance ...SmiNode) error {
+	t, err := n.GetEffectiveType(compliance...)
+	if err != nil {
+		return err
+	}
+	return t.Validate(value)
+}
+
+// ValidateWrite checks a value to be set against the effective type of the
+// node, with the WRITE-SYNTAX, or otherwise SYNTAX, refinements of the given
+// MODULE-COMPLIANCE nodes applied. Nodes that are not read-write, which
+// includes read-create, are rejected with ErrNotWritable.
+func (n SmiNode) ValidateWrite(value interface{}, compliance ...SmiNode) error {
+	if n.Access != types.AccessReadWrite {
+		return fmt.Errorf("%w: %s is %s", ErrNotWritable, n.Name, n.Access)
+	}
+	t, err := n.GetEffectiveWriteType(compliance...)
+	if err != nil {
+		return err
+	}
+	return t.Validate(value)
+}
+
+func (t SmiType) Validate(value interface{}) error {
+	return t.GetEffectiveType().Validate(value)
+}
@@ -0,0 +1,61 @@
+package gosmi_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/models"
+)
+
+func TestValidateWrite(t *testing.T) {
+	tests := []struct {
+		source     string
+		node       string
+		compliance string
+		value      interface{}
+		err        error
+	}{
+		{"Size", "testName", "", "short", nil},
+		{"Restricted size", "testName", "", strings.Repeat("x", 17), models.ErrInvalidSize},
+		{"DisplayString size", "sysContact", "", strings.Repeat("x", 256), models.ErrInvalidSize},
+		{"Range", "testEntryValue", "", 100, nil},
+		{"Out of range", "testEntryValue", "", 101, models.ErrOutOfRange},
+		{"Enum", "testEntryStatus", "", "createAndGo", nil},
+		{"Unknown enum", "testEntryStatus", "", 7, models.ErrUnknownEnum},
+		{"Bits", "testFlags", "", []byte{0xe0, 0x40}, nil},
+		{"Unknown bit", "testFlags", "", []byte{0x10}, models.ErrUnknownBit},
+		{"Invalid type", "testEntryValue", "", []byte{1}, models.ErrInvalidType},
+		// The SYNTAX refinements apply when there is no WRITE-SYNTAX
+		{"Without refinement", "ifAdminStatus", "", 3, nil},
+		{"Refined enum", "ifAdminStatus", "ifCompliance3", 3, models.ErrUnknownEnum},
+		{"Refined enum in range", "ifAdminStatus", "ifCompliance3", "up", nil},
+		{"Refined size", "ifAlias", "testCompliance", strings.Repeat("x", 17), models.ErrInvalidSize},
+		{"Other compliance", "ifAlias", "ifCompliance3", strings.Repeat("x", 17), nil},
+		{"Read-only", "testTemperature", "", 10, gosmi.ErrNotWritable},
+		{"Read-only column", "testEntryPeerType", "", 1, gosmi.ErrNotWritable},
+		{"Not accessible", "testEntryName", "", "a", gosmi.ErrNotWritable},
+	}
+	for _, test := range tests {
+		var compliance []gosmi.SmiNode
+		if test.compliance != "" {
+			compliance = append(compliance, getNode(t, test.compliance))
+		}
+		err := getNode(t, test.node).ValidateWrite(test.value, compliance...)
+		if test.err == nil {
+			if err != nil {
+				t.Errorf("%s: %s ValidateWrite(%v): %v", test.source, test.node, test.value, err)
+			}
+			continue
+		}
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: %s ValidateWrite(%v) = %v, want %v", test.source, test.node, test.value, err, test.err)
+		}
+	}
+
+	// Read-only nodes can still be validated as values
+	if err := getNode(t, "testTemperature").Validate(1001); !errors.Is(err, models.ErrOutOfRange) {
+		t.Errorf("testTemperature Validate(1001) = %v, want %v", err, models.ErrOutOfRange)
+	}
+}
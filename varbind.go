@@ -0,0 +1,278 @@
+package gosmi
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/smi"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// IndexValue is the value of one index object of a conceptual row, decoded
+// from the instance of a column
+type IndexValue struct {
+	Node  SmiNode
+	Oid   types.Oid // Sub-identifiers of the instance that encode the value
+	Value models.Value
+}
+
+// Varbind is a variable binding annotated with the MIB information of its
+// OID
+type Varbind struct {
+	Oid      types.Oid
+	Node     SmiNode
+	Module   string
+	Kind     types.NodeKind
+	Instance types.Oid    // Sub-identifiers after the OID of the node
+	Index    []IndexValue // Decoded instance of a column
+	Value    models.Value
+	Units    string
+	Err      error // Set if the node or instance could not be decoded, or the encoded value is malformed
+}
+
+// String returns the varbind in the form IF-MIB::ifOperStatus.3 = down(2), or
+// with the numeric OID if it does not resolve to a node. The module is
+// omitted if it is not known.
+func (vb Varbind) String() string {
+	name := vb.Oid.String()
+	if vb.Node.Name != "" {
+		name = vb.Node.Name
+		if vb.Module != "" {
+			name = vb.Module + "::" + name
+		}
+		if len(vb.Instance) > 0 {
+			name += "." + vb.Instance.String()
+		}
+	}
+	return name + " = " + vb.Value.String()
+}
+
+type indexValueJSON struct {
+	Name  string       `json:"name"`
+	Value models.Value `json:"value"`
+}
+
+type varbindJSON struct {
+	Oid      string           `json:"oid"`
+	Name     string           `json:"name,omitempty"`
+	Instance string           `json:"instance,omitempty"`
+	Index    []indexValueJSON `json:"index,omitempty"`
+	Value    models.Value     `json:"value"`
+	Error    string           `json:"error,omitempty"`
+}
+
+// MarshalJSON encodes the varbind as a JSON object with the OID, the
+// module-qualified name of the node, the instance, the values of the index
+// objects and the value, which is encoded as by Value.MarshalJSON. The node
+// itself is left out, as it can be looked up by name.
+func (vb Varbind) MarshalJSON() ([]byte, error) {
+	out := varbindJSON{
+		Oid:   vb.Oid.String(),
+		Value: vb.Value,
+	}
+	if vb.Node.Name != "" {
+		out.Name = vb.Node.Name
+		if vb.Module != "" {
+			out.Name = vb.Module + "::" + out.Name
+		}
+	}
+	if len(vb.Instance) > 0 {
+		out.Instance = vb.Instance.String()
+	}
+	for _, index := range vb.Index {
+		name := index.Node.Name
+		if module := index.Node.GetModule().Name; module != "" {
+			name = module + "::" + name
+		}
+		out.Index = append(out.Index, indexValueJSON{Name: name, Value: index.Value})
+	}
+	if vb.Err != nil {
+		out.Error = vb.Err.Error()
+	}
+	return json.Marshal(out)
+}
+
+// varbindNode holds everything needed to decode varbinds of one node
+type varbindNode struct {
+	node      SmiNode
+	module    string
+	formatter models.ValueFormatter
+	index     []varbindIndex
+	implied   bool
+
+	indexTypes []models.Type // Types of the index objects, if they are all known
+}
+
+type varbindIndex struct {
+	node      SmiNode
+	formatter models.ValueFormatter
+}
+
+// VarbindDecoder decodes varbinds into Varbind records. The MIB information
+// of each node is cached by OID prefix, so the decoder must be reset, or
+// replaced, after loading or unloading modules. It is safe for concurrent
+// use.
+type VarbindDecoder struct {
+	flags []models.Format
+
+	mu      sync.RWMutex
+	nodes   map[string]*varbindNode
+	lengths []int // Lengths of the cached OID prefixes, longest first
+}
+
+// NewVarbindDecoder returns a decoder that formats values with the given
+// format flags
+func NewVarbindDecoder(flags ...models.Format) *VarbindDecoder {
+	return &VarbindDecoder{
+		flags: flags,
+		nodes: make(map[string]*varbindNode),
+	}
+}
+
+// Reset clears the cache of the decoder
+func (d *VarbindDecoder) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.nodes = make(map[string]*varbindNode)
+	d.lengths = nil
+}
+
+func oidKey(oid types.Oid) string {
+	key := make([]byte, 4*len(oid))
+	for i, subId := range oid {
+		binary.BigEndian.PutUint32(key[4*i:], uint32(subId))
+	}
+	return string(key)
+}
+
+func (d *VarbindDecoder) lookup(oid types.Oid) *varbindNode {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, length := range d.lengths {
+		if length > len(oid) {
+			continue
+		}
+		if n, ok := d.nodes[oidKey(oid[:length])]; ok {
+			return n
+		}
+	}
+	return nil
+}
+
+func (d *VarbindDecoder) newVarbindNode(smiNode *types.SmiNode) *varbindNode {
+	node := CreateNode(smiNode)
+	n := &varbindNode{
+		node:   node,
+		module: node.GetModule().Name,
+	}
+	if node.Type != nil {
+		n.formatter = node.Type.GetValueFormatter(d.flags...)
+	}
+	if node.Kind == types.NodeColumn {
+		n.implied = node.GetImplied()
+		for _, column := range node.GetIndex() {
+			index := varbindIndex{node: column}
+			if column.Type != nil {
+				index.formatter = column.Type.GetValueFormatter(d.flags...)
+				n.indexTypes = append(n.indexTypes, *column.Type)
+			}
+			n.index = append(n.index, index)
+		}
+	}
+	return n
+}
+
+func (d *VarbindDecoder) getNode(oid types.Oid) *varbindNode {
+	if n := d.lookup(oid); n != nil {
+		return n
+	}
+	smiNode := smi.GetNodeByOID(oid)
+	if smiNode == nil {
+		return nil
+	}
+	n := d.newVarbindNode(smiNode)
+	if smi.GetFirstChildNode(smiNode) != nil {
+		// Only leaf nodes are cached, since an OID under any other node could
+		// belong to a descendant that has not been looked up yet
+		return n
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	key := oidKey(smiNode.Oid)
+	if _, ok := d.nodes[key]; !ok {
+		d.nodes[key] = n
+		i := sort.Search(len(d.lengths), func(i int) bool { return d.lengths[i] <= smiNode.OidLen })
+		if i == len(d.lengths) || d.lengths[i] != smiNode.OidLen {
+			d.lengths = append(d.lengths, 0)
+			copy(d.lengths[i+1:], d.lengths[i:])
+			d.lengths[i] = smiNode.OidLen
+		}
+	}
+	return n
+}
+
+// Decode annotates the varbind with the node that the OID resolves to, and
+// decodes the instance and the value. An error is returned only if the OID
+// does not resolve to any node. An instance that cannot be decoded sets Err,
+// while a value that cannot be formatted with the type of the node sets only
+// Value.Err, so that it does not invalidate the rest of the varbind.
+func (d *VarbindDecoder) Decode(oid types.Oid, value interface{}) (vb Varbind, err error) {
+	n := d.getNode(oid)
+	if n == nil {
+		err = fmt.Errorf("Could not find node for OID %s", oid)
+		return
+	}
+	vb = Varbind{
+		Oid:      oid,
+		Node:     n.node,
+		Module:   n.module,
+		Kind:     n.node.Kind,
+		Instance: oid[n.node.OidLen:],
+	}
+	if n.node.Type != nil {
+		vb.Units = n.node.Type.Units
+	}
+	if n.formatter != nil {
+		vb.Value = n.formatter(value)
+	} else {
+		vb.Value = models.Value{Raw: value}
+	}
+	if len(n.index) > 0 {
+		vb.Index, vb.Err = n.decodeIndex(vb.Instance)
+	}
+	return
+}
+
+func (n *varbindNode) decodeIndex(instance types.Oid) ([]IndexValue, error) {
+	if len(n.indexTypes) != len(n.index) {
+		for _, column := range n.index {
+			if column.node.Type == nil {
+				return nil, fmt.Errorf("Index %s has no type", column.node.Name)
+			}
+		}
+	}
+	values, lengths, err := models.DecodeInstance(n.indexTypes, instance, n.implied)
+	if err != nil {
+		return nil, err
+	}
+	index := make([]IndexValue, len(values))
+	for i, value := range values {
+		index[i] = IndexValue{
+			Node:  n.index[i].node,
+			Oid:   instance[:lengths[i]],
+			Value: n.index[i].formatter(value),
+		}
+		instance = instance[lengths[i]:]
+	}
+	return index, nil
+}
+
+// DecodeVarbind decodes a single varbind without caching. Use a
+// VarbindDecoder to decode many varbinds.
+func DecodeVarbind(oid types.Oid, value interface{}, flags ...models.Format) (Varbind, error) {
+	return NewVarbindDecoder(flags...).Decode(oid, value)
+}
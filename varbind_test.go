@@ -0,0 +1,169 @@
+package gosmi_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/models"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+func TestVarbindString(t *testing.T) {
+	node, err := gosmi.GetNode("ifDescr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		source string
+		vb     gosmi.Varbind
+		want   string
+	}{
+		{"Module", gosmi.Varbind{Node: node, Module: "IF-MIB", Instance: types.Oid{1}, Value: models.Value{Raw: []byte("eth0")}}, "IF-MIB::ifDescr.1 = eth0"},
+		{"No module", gosmi.Varbind{Node: node, Instance: types.Oid{1}, Value: models.Value{Raw: []byte("eth0")}}, "ifDescr.1 = eth0"},
+		{"No node", gosmi.Varbind{Oid: types.Oid{1, 3, 6, 1, 2, 1, 2, 2, 1, 2, 1}, Value: models.Value{Raw: []byte("eth0")}}, "1.3.6.1.2.1.2.2.1.2.1 = eth0"},
+	}
+	for _, test := range tests {
+		if s := test.vb.String(); s != test.want {
+			t.Errorf("%s: String = %q, want %q", test.source, s, test.want)
+		}
+	}
+}
+
+func TestVarbindDecoderCache(t *testing.T) {
+	// Decoded in order twice, so that the second pass is served from the
+	// cache. Non-leaf nodes are not cached, so that they cannot shadow their
+	// descendants.
+	tests := []struct {
+		oid  string
+		want string
+	}{
+		{"1.3.6.1.2.1.2.99.1", "IF-MIB::interfaces.99.1"},
+		{"1.3.6.1.2.1.2.2.1.2.1", "IF-MIB::ifDescr.1"},
+		{"1.3.6.1.2.1.1.3.0", "SNMPv2-MIB::sysUpTime.0"},
+		{"1.3.6.1.2.1.31.1.1.1.1.1", "IF-MIB::ifName.1"},
+		{"1.3.6.1.2.1.2.2.1.2.2", "IF-MIB::ifDescr.2"},
+		{"1.3.6.1.4.1.99999.1.10.1.3.1.97.1.3", "TEST-MIB::testEntryValue.1.97.1.3"},
+		{"1.3.6.1.2.1.2.2.1", "IF-MIB::ifEntry"},
+		{"1.3.6.1.2.1.2.2.1.8.3", "IF-MIB::ifOperStatus.3"},
+	}
+	d := gosmi.NewVarbindDecoder()
+	for pass := 1; pass <= 3; pass++ {
+		for _, test := range tests {
+			vb, err := d.Decode(types.OidMustFromString(test.oid), nil)
+			if err != nil {
+				t.Errorf("Pass %d: Decode %s: %v", pass, test.oid, err)
+				continue
+			}
+			name := vb.Module + "::" + vb.Node.Name
+			if len(vb.Instance) > 0 {
+				name += "." + vb.Instance.String()
+			}
+			if name != test.want {
+				t.Errorf("Pass %d: Decode %s = %s, want %s", pass, test.oid, name, test.want)
+			}
+		}
+		if pass == 2 {
+			d.Reset()
+		}
+	}
+}
+
+func TestVarbindJSON(t *testing.T) {
+	vb, err := gosmi.DecodeVarbind(types.OidMustFromString("1.3.6.1.4.1.99999.1.10.1.4.1.97.1.3"), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(vb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if want := []string{"index", "instance", "name", "oid", "value"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Marshal = %s, want keys %v", data, want)
+	}
+	var out struct {
+		Oid      string
+		Name     string
+		Instance string
+		Index    []struct {
+			Name  string
+			Value models.Value
+		}
+		Value models.Value
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Oid != "1.3.6.1.4.1.99999.1.10.1.4.1.97.1.3" || out.Name != "TEST-MIB::testEntryStatus" || out.Instance != "1.97.1.3" {
+		t.Errorf("Marshal = %s, want testEntryStatus.1.97.1.3", data)
+	}
+	if len(out.Index) != 2 || out.Index[0].Name != "TEST-MIB::testEntryName" || out.Index[0].Value.Formatted != "a" || out.Index[1].Name != "TEST-MIB::testEntryOid" || out.Index[1].Value.Formatted != "1.3" {
+		t.Errorf("Marshal = %s, want index testEntryName a and testEntryOid 1.3", data)
+	}
+	if out.Value.Raw != models.RowStatusActive || out.Value.Formatted != "active(1)" {
+		t.Errorf("Marshal = %s, want value active(1)", data)
+	}
+
+	// The instance of testEntryValue.5.97 is too short for a name of length 5
+	vb, _ = gosmi.DecodeVarbind(types.OidMustFromString("1.3.6.1.4.1.99999.1.10.1.3.5.97"), 42)
+	if vb.Err == nil {
+		t.Fatal("Decode testEntryValue.5.97: got no error")
+	}
+	var withError struct {
+		Error string
+	}
+	if data, err := json.Marshal(vb); err != nil || json.Unmarshal(data, &withError) != nil || withError.Error != vb.Err.Error() {
+		t.Errorf("Marshal = %s, %v, want error %q", data, err, vb.Err)
+	}
+}
+
+func TestVarbindDecode(t *testing.T) {
+	tests := []struct {
+		source    string
+		oid       string
+		value     interface{}
+		formatted string
+		units     string
+		index     []string
+		err       bool
+		valueErr  bool
+	}{
+		{"Scalar with units", "1.3.6.1.4.1.99999.1.1.0", 215, "21.5", "degrees Celsius", nil, false, false},
+		{"Column with units", "1.3.6.1.2.1.2.2.1.5.3", 100000000, "100000000", "bits per second", []string{"ifIndex=3"}, false, false},
+		{"Column with string and implied index", "1.3.6.1.4.1.99999.1.10.1.3.1.97.1.3", 42, "42", "", []string{"testEntryName=a", "testEntryOid=1.3"}, false, false},
+		// The name of length 5 has only one sub-identifier
+		{"Undecodable instance", "1.3.6.1.4.1.99999.1.10.1.3.5.97", 42, "42", "", nil, true, false},
+		{"Invalid value", "1.3.6.1.2.1.1.3.0", []byte("x"), "", "", nil, false, true},
+	}
+	for _, test := range tests {
+		vb, err := gosmi.DecodeVarbind(types.OidMustFromString(test.oid), test.value)
+		if err != nil {
+			t.Errorf("%s: %v", test.source, err)
+			continue
+		}
+		if vb.Value.Formatted != test.formatted || vb.Units != test.units {
+			t.Errorf("%s: Decode = %q with units %q, want %q with units %q", test.source, vb.Value.Formatted, vb.Units, test.formatted, test.units)
+		}
+		var index []string
+		for _, value := range vb.Index {
+			index = append(index, value.Node.Name+"="+value.Value.Formatted)
+		}
+		if !reflect.DeepEqual(index, test.index) {
+			t.Errorf("%s: Index = %v, want %v", test.source, index, test.index)
+		}
+		if (vb.Err != nil) != test.err || (vb.Value.Err != nil) != test.valueErr {
+			t.Errorf("%s: Err = %v and Value.Err = %v, want %t and %t", test.source, vb.Err, vb.Value.Err, test.err, test.valueErr)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package gosmi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sleepinggenius2/gosmi/smi"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+type WalkAction = smi.WalkAction
+
+const (
+	WalkContinue = smi.WalkContinue
+	WalkSkip     = smi.WalkSkip
+	WalkStop     = smi.WalkStop
+)
+
+type WalkFunc func(node SmiNode) WalkAction
+
+type WalkFilter struct {
+	Kind    types.NodeKind // Mask of node kinds to visit, defaults to types.NodeAny
+	Status  []types.Status // Statuses to visit, defaults to all
+	Modules []string       // Modules to visit, in order of precedence, defaults to all loaded modules
+}
+
+func (f WalkFilter) match(smiNode *types.SmiNode) bool {
+	if f.Kind != types.NodeUnknown && f.Kind != types.NodeAny && smiNode.NodeKind&f.Kind == 0 {
+		return false
+	}
+	if len(f.Status) == 0 {
+		return true
+	}
+	for _, status := range f.Status {
+		if smiNode.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+func walk(oid types.Oid, fn WalkFunc, filter []WalkFilter) error {
+	var f WalkFilter
+	if len(filter) > 0 {
+		f = filter[0]
+	}
+	var smiModules []*types.SmiModule
+	for _, name := range f.Modules {
+		if !smi.IsLoaded(name) {
+			return fmt.Errorf("Could not find module named %s", name)
+		}
+		smiModules = append(smiModules, smi.GetModule(name))
+	}
+	if fn == nil {
+		return nil
+	}
+	smi.Walk(oid, smiModules, func(smiNode *types.SmiNode) WalkAction {
+		if !f.match(smiNode) {
+			return WalkContinue
+		}
+		return fn(CreateNode(smiNode))
+	})
+	return nil
+}
+
+// Walk visits every node in the OID tree across all loaded modules, in OID
+// order. Nodes rejected by the filter are not passed to fn, but their
+// children are still visited. An error is returned if the filter names a
+// module that is not loaded.
+func Walk(fn WalkFunc, filter ...WalkFilter) error {
+	return walk(nil, fn, filter)
+}
+
+// WalkSubtree is like Walk, but starts at the given OID
+func WalkSubtree(oid types.Oid, fn WalkFunc, filter ...WalkFilter) error {
+	if len(oid) == 0 {
+		return errors.New("OID is empty")
+	}
+	return walk(oid, fn, filter)
+}
+
+func (n SmiNode) Walk(fn WalkFunc, filter ...WalkFilter) error {
+	return WalkSubtree(n.Oid, fn, filter...)
+}
@@ -0,0 +1,97 @@
+package gosmi_test
+
+import (
+	"testing"
+
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// walkNames walks the subtree of the node, returning the names of the nodes
+// visited
+func walkNames(t *testing.T, name string, action func(gosmi.SmiNode) gosmi.WalkAction, filter ...gosmi.WalkFilter) []string {
+	t.Helper()
+	names := []string{}
+	err := getNode(t, name).Walk(func(node gosmi.SmiNode) gosmi.WalkAction {
+		names = append(names, node.Name)
+		if action == nil {
+			return gosmi.WalkContinue
+		}
+		return action(node)
+	}, filter...)
+	if err != nil {
+		t.Fatalf("Walk %s: %v", name, err)
+	}
+	return names
+}
+
+func TestWalkPruning(t *testing.T) {
+	tests := []struct {
+		source string
+		node   string
+		action func(gosmi.SmiNode) gosmi.WalkAction
+		names  []string
+	}{
+		{"Continue", "ifXTable", nil, []string{"ifXTable", "ifXEntry", "ifName", "ifHCInOctets", "ifAlias"}},
+		{"Skip", "ifMIB", func(node gosmi.SmiNode) gosmi.WalkAction {
+			if node.Kind == types.NodeRow || node.Name == "ifConformance" {
+				return gosmi.WalkSkip
+			}
+			return gosmi.WalkContinue
+		}, []string{"ifMIB", "ifMIBObjects", "ifXTable", "ifXEntry", "ifConformance"}},
+		{"Stop", "ifEntry", func(node gosmi.SmiNode) gosmi.WalkAction {
+			if node.Name == "ifMtu" {
+				return gosmi.WalkStop
+			}
+			return gosmi.WalkContinue
+		}, []string{"ifEntry", "ifIndex", "ifDescr", "ifMtu"}},
+	}
+	for _, test := range tests {
+		if names := walkNames(t, test.node, test.action); !equalStrings(names, test.names) {
+			t.Errorf("%s: walked %v, want %v", test.source, names, test.names)
+		}
+	}
+}
+
+func TestWalkFilter(t *testing.T) {
+	tests := []struct {
+		source string
+		node   string
+		filter gosmi.WalkFilter
+		names  []string
+	}{
+		// Rejected nodes are not visited, but their children are
+		{"Kind", "ifXTable", gosmi.WalkFilter{Kind: types.NodeColumn}, []string{"ifName", "ifHCInOctets", "ifAlias"}},
+		{"Kind mask", "testTable", gosmi.WalkFilter{Kind: types.NodeTable | types.NodeRow}, []string{"testTable", "testEntry"}},
+		{"Status", "testV1", gosmi.WalkFilter{Status: []types.Status{types.StatusMandatory}}, []string{"testV1Message"}},
+		{"No status", "testV1", gosmi.WalkFilter{Status: []types.Status{types.StatusCurrent, types.StatusDeprecated}}, []string{}},
+		{"Module", "enterprises", gosmi.WalkFilter{Modules: []string{"TEST-V1-MIB"}, Kind: types.NodeScalar}, []string{"testV1Message"}},
+		// The first module that defines a node takes precedence
+		{"Module precedence", "testObjects", gosmi.WalkFilter{Modules: []string{"TEST-ALIAS-MIB", "TEST-MIB"}, Kind: types.NodeNode}, []string{"testAlias"}},
+		{"Module precedence reversed", "testObjects", gosmi.WalkFilter{Modules: []string{"TEST-MIB", "TEST-ALIAS-MIB"}, Kind: types.NodeNode}, []string{"testObjects"}},
+	}
+	for _, test := range tests {
+		if names := walkNames(t, test.node, nil, test.filter); !equalStrings(names, test.names) {
+			t.Errorf("%s: walked %v, want %v", test.source, names, test.names)
+		}
+	}
+}
+
+func TestWalkErrors(t *testing.T) {
+	called := false
+	fn := func(gosmi.SmiNode) gosmi.WalkAction {
+		called = true
+		return gosmi.WalkContinue
+	}
+	for _, modules := range [][]string{{"NO-SUCH-MIB"}, {"TEST-MIB", "NO-SUCH-MIB"}} {
+		if err := gosmi.Walk(fn, gosmi.WalkFilter{Modules: modules}); err == nil {
+			t.Errorf("Walk of modules %v: got no error", modules)
+		}
+	}
+	if err := gosmi.WalkSubtree(nil, fn); err == nil {
+		t.Error("WalkSubtree of an empty OID: got no error")
+	}
+	if called {
+		t.Error("Walk called fn despite an error")
+	}
+}